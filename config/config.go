@@ -5,8 +5,32 @@ import "time"
 var Config *MapConfig
 
 type MapConfig struct {
-	AppHost            string  		 `mapstructure:"APP_HOST"`
-	DbConnectionString string  		 `mapstructure:"DB_CONNECTION_STRING"`
-	JwtSecretKey       string  		 `mapstructure:"JWT_SECRET_KEY"`
-	JwtExpiresIn       time.Duration `mapstructure:"JWT_EXPIRE_DURATION"`
+	AppHost             string        `mapstructure:"APP_HOST"`
+	DbConnectionString  string        `mapstructure:"DB_CONNECTION_STRING"`
+	DbAutoMigrate       bool          `mapstructure:"DB_AUTO_MIGRATE"`
+	JwtSecretKey        string        `mapstructure:"JWT_SECRET_KEY"`
+	JwtExpiresIn        time.Duration `mapstructure:"JWT_EXPIRE_DURATION"`
+	JwtRefreshExpiresIn time.Duration `mapstructure:"JWT_REFRESH_EXPIRE_DURATION"`
+
+	// Интервалы фоновых задач; нулевой отключает задачу
+	TokensSweepInterval        time.Duration `mapstructure:"TOKENS_SWEEP_INTERVAL"`
+	SuggestionsRefreshInterval time.Duration `mapstructure:"SUGGESTIONS_REFRESH_INTERVAL"`
+	MoviesPublishInterval      time.Duration `mapstructure:"MOVIES_PUBLISH_INTERVAL"`
+	MediaGCInterval            time.Duration `mapstructure:"MEDIA_GC_INTERVAL"`
+	MediaGCGracePeriod         time.Duration `mapstructure:"MEDIA_GC_GRACE_PERIOD"` // файлы без ссылок моложе этого срока не удаляются
+	MediaCacheTrimInterval     time.Duration `mapstructure:"MEDIA_CACHE_TRIM_INTERVAL"`
+	MediaCacheMaxBytes         int64         `mapstructure:"MEDIA_CACHE_MAX_BYTES"` // предел MEDIA_CACHE_DIR, сверх него удаляются давно не запрошенные файлы
+
+	StorageDriver   string        `mapstructure:"STORAGE_DRIVER"` // local или s3
+	StorageLocalDir string        `mapstructure:"STORAGE_LOCAL_DIR"`
+	MediaBaseUrl    string        `mapstructure:"MEDIA_BASE_URL"`  // префикс ссылок на STORAGE_LOCAL_DIR, его раздаёт /media/*
+	MediaCacheDir   string        `mapstructure:"MEDIA_CACHE_DIR"` // копии объектов S3 и уменьшенные варианты
+	S3Endpoint      string        `mapstructure:"S3_ENDPOINT"`
+	S3Region        string        `mapstructure:"S3_REGION"`
+	S3Bucket        string        `mapstructure:"S3_BUCKET"`
+	S3AccessKey     string        `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey     string        `mapstructure:"S3_SECRET_KEY"`
+	S3UsePathStyle  bool          `mapstructure:"S3_USE_PATH_STYLE"`
+	S3PublicBaseUrl string        `mapstructure:"S3_PUBLIC_BASE_URL"`
+	S3PresignExpiry time.Duration `mapstructure:"S3_PRESIGN_EXPIRY"`
 }
@@ -83,9 +83,21 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid input or poster dimensions",
                         "schema": {
-                            "$ref": "#/definitions/models.ApiError"
+                            "$ref": "#/definitions/models.UploadError"
+                        }
+                    },
+                    "413": {
+                        "description": "Poster larger than 5 MB",
+                        "schema": {
+                            "$ref": "#/definitions/models.UploadError"
+                        }
+                    },
+                    "415": {
+                        "description": "Poster is not JPEG, PNG or WebP",
+                        "schema": {
+                            "$ref": "#/definitions/models.UploadError"
                         }
                     },
                     "500": {
@@ -452,7 +464,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "file",
-                        "description": "Poster image",
+                        "description": "Poster image: JPEG, PNG or WebP up to 5 MB, from 200x200 to 4000x4000",
                         "name": "poster",
                         "in": "formData",
                         "required": true
@@ -471,7 +483,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ApiError"
+                            "$ref": "#/definitions/models.UploadError"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.UploadError"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/models.UploadError"
                         }
                     },
                     "500": {
@@ -620,9 +644,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/movieTypes": {
+        "/admin/homepage": {
             "get": {
-                "description": "Get a list of all movie types",
+                "description": "Retrieve every homepage rail in display order, including rails outside their visibility window",
                 "consumes": [
                     "application/json"
                 ],
@@ -630,29 +654,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MovieTypes"
+                    "homepage"
                 ],
-                "summary": "Get all movie types",
+                "summary": "Get homepage layout",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Homepage rails",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.MovieType"
+                                "$ref": "#/definitions/models.HomepageRail"
                             }
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new movie type with the specified name",
+            }
+        },
+        "/admin/homepage/order": {
+            "put": {
+                "description": "Set the display order of the homepage. rail_ids must list every rail exactly once",
                 "consumes": [
                     "application/json"
                 ],
@@ -660,23 +686,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MovieTypes"
+                    "homepage"
                 ],
-                "summary": "Create a new movie type",
+                "summary": "Reorder homepage rails",
                 "parameters": [
                     {
-                        "description": "Movie Type Information",
-                        "name": "movieType",
+                        "description": "Rail Ids in display order",
+                        "name": "order",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/admin.createMovieTypeRequest"
+                            "$ref": "#/definitions/admin.reorderRailsRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Movie Type ID",
+                        "description": "Order saved"
+                    },
+                    "400": {
+                        "description": "Invalid order",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/homepage/rails": {
+            "post": {
+                "description": "Add a rail to the end of the homepage. Types: category (category_id), genre (genre_id),\nhand_picked (movie_ids in display order), continue_watching, new_releases",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "homepage"
+                ],
+                "summary": "Create a homepage rail",
+                "parameters": [
+                    {
+                        "description": "Rail settings",
+                        "name": "rail",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.homepageRailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Id of the created rail",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -685,13 +754,13 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid rail settings",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -699,9 +768,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/movieTypes/{id}": {
+        "/admin/homepage/rails/{id}": {
             "get": {
-                "description": "Get a movie type by its ID",
+                "description": "Retrieve a homepage rail by its Id",
                 "consumes": [
                     "application/json"
                 ],
@@ -709,13 +778,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MovieTypes"
+                    "homepage"
                 ],
-                "summary": "Get movie type by ID",
+                "summary": "Get homepage rail by Id",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie Type ID",
+                        "description": "Rail Id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -723,19 +792,19 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Homepage rail",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieType"
+                            "$ref": "#/definitions/models.HomepageRail"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid rail Id",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Rail not found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -743,7 +812,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update an existing movie type by its ID",
+                "description": "Replace the settings of a homepage rail. Its position is changed with PUT /admin/homepage/order",
                 "consumes": [
                     "application/json"
                 ],
@@ -751,51 +820,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MovieTypes"
+                    "homepage"
                 ],
-                "summary": "Update an existing movie type",
+                "summary": "Update a homepage rail",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie Type ID",
+                        "description": "Rail Id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Movie Type Information",
-                        "name": "movieType",
+                        "description": "Rail settings",
+                        "name": "rail",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.MovieType"
+                            "$ref": "#/definitions/admin.homepageRailRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Movie type updated successfully",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                        "description": "Rail updated"
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid rail settings",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Rail not found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -803,7 +866,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a movie type by its ID",
+                "description": "Remove a rail from the homepage",
                 "consumes": [
                     "application/json"
                 ],
@@ -811,13 +874,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MovieTypes"
+                    "homepage"
                 ],
-                "summary": "Delete a movie type",
+                "summary": "Delete a homepage rail",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie Type ID",
+                        "description": "Rail Id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -825,7 +888,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Movie type deleted successfully",
+                        "description": "Rail deleted successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -834,13 +897,13 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid rail Id",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -848,94 +911,119 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/movies": {
-            "get": {
-                "description": "Get a list of all movies",
-                "consumes": [
-                    "application/json"
+        "/admin/media/gc": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Finds files in the media storage that no movie, genre or age rating references and that are older\nthan the grace period. By default only reports them; pass dry_run=false to delete",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movies"
+                    "media"
+                ],
+                "summary": "Collect orphaned media",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only report orphaned files (default true)",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum age of a file to delete, e.g. 48h (default MEDIA_GC_GRACE_PERIOD, at least 10m)",
+                        "name": "grace_period",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all movies",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Orphaned files and what was deleted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Movie"
-                            }
+                            "$ref": "#/definitions/models.MediaGCReport"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Invalid dry_run or grace_period",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new movie with the specified information",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/admin/media/movies/{id}/items/{mediaId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Removes a screenshot from the gallery and deletes its file",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movies"
+                    "media"
                 ],
-                "summary": "Create a new movie",
+                "summary": "Delete a screenshot",
                 "parameters": [
                     {
-                        "description": "Movie information",
-                        "name": "movie",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/admin.createMovieRequest"
-                        }
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Screenshot ID",
+                        "name": "mediaId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Movie ID",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
-                        }
+                        "description": "Screenshot deleted"
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid ID",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Screenshot not found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     }
                 }
-            }
-        },
-        "/admin/movies/{Id}/seasons": {
-            "post": {
-                "description": "Adds a new season with episodes to a movie",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Changes the caption, alt text or hero flag of a screenshot. Marking a screenshot as hero\nclears the flag on the others",
                 "consumes": [
                     "application/json"
                 ],
@@ -943,9 +1031,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "content"
+                    "media"
                 ],
-                "summary": "Add seasons and episodes to a movie",
+                "summary": "Update a screenshot",
                 "parameters": [
                     {
                         "type": "integer",
@@ -955,29 +1043,37 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Season and episodes data",
-                        "name": "request",
+                        "type": "integer",
+                        "description": "Screenshot ID",
+                        "name": "mediaId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "patch",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/admin.CreateSeasonRequest"
+                            "$ref": "#/definitions/models.MovieMediaPatch"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Seasons and episodes added successfully",
+                        "description": "Updated screenshot",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "message": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/models.MovieMediaItem"
                         }
                     },
                     "400": {
-                        "description": "Invalid movie id or payload",
+                        "description": "Invalid data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie or screenshot not found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -991,9 +1087,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/movies/{Id}/seasons/{seasonId}": {
+        "/admin/media/movies/{id}/order": {
             "put": {
-                "description": "Updates an existing season's details and episodes",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sets the gallery order of the movie. ids must list every screenshot of the movie exactly once",
                 "consumes": [
                     "application/json"
                 ],
@@ -1001,54 +1102,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "content"
+                    "media"
                 ],
-                "summary": "Update season details",
+                "summary": "Reorder movie screenshots",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Movie ID",
-                        "name": "Id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Season ID",
-                        "name": "seasonId",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Season and episode update data",
-                        "name": "request",
+                        "description": "Screenshot ids in display order",
+                        "name": "order",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/admin.UpdateSeasonRequest"
+                            "$ref": "#/definitions/admin.reorderMediaRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Season and episodes updated successfully",
+                        "description": "Cover and gallery in the new order",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "message": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/models.MovieMedia"
                         }
                     },
                     "400": {
-                        "description": "Invalid movie Id or seasonId or payload",
+                        "description": "Invalid order",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "404": {
-                        "description": "Season not found",
+                        "description": "Movie not found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -1060,55 +1149,80 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Removes a season and its episodes from a movie",
-                "consumes": [
+            }
+        },
+        "/admin/movieTypes": {
+            "get": {
+                "description": "Get a list of all movie types",
+                "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "content"
+                    "MovieTypes"
                 ],
-                "summary": "Delete a season from a movie",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Movie ID",
-                        "name": "Id",
-                        "in": "path",
-                        "required": true
+                "summary": "Get all movie types",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MovieType"
+                            }
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new movie type with the specified name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MovieTypes"
+                ],
+                "summary": "Create a new movie type",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Season ID",
-                        "name": "seasonId",
-                        "in": "path",
-                        "required": true
+                        "description": "Movie Type Information",
+                        "name": "movieType",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.createMovieTypeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Season deleted successfully",
+                        "description": "Movie Type ID",
                         "schema": {
                             "type": "object",
-                            "properties": {
-                                "message": {
-                                    "type": "string"
-                                }
+                            "additionalProperties": {
+                                "type": "integer"
                             }
                         }
                     },
                     "400": {
-                        "description": "Invalid movie id or seasonId",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -1116,9 +1230,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/movies/{id}": {
+        "/admin/movieTypes/{id}": {
             "get": {
-                "description": "Get a movie by its ID",
+                "description": "Get a movie type by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1126,13 +1240,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Movies"
+                    "MovieTypes"
                 ],
-                "summary": "Get movie by ID",
+                "summary": "Get movie type by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID",
+                        "description": "Movie Type ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1142,7 +1256,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Movie"
+                            "$ref": "#/definitions/models.MovieType"
                         }
                     },
                     "400": {
@@ -1159,8 +1273,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete a movie by its ID",
+            "put": {
+                "description": "Update an existing movie type by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1168,21 +1282,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Movies"
+                    "MovieTypes"
                 ],
-                "summary": "Delete movie",
+                "summary": "Update an existing movie type",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID",
+                        "description": "Movie Type ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Updated Movie Type Information",
+                        "name": "movieType",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieType"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Movie deleted successfully",
+                        "description": "Movie type updated successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1210,8 +1333,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "description": "Update an existing movie by its ID",
+            "delete": {
+                "description": "Delete a movie type by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1219,30 +1342,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Movies"
+                    "MovieTypes"
                 ],
-                "summary": "Update movie",
+                "summary": "Delete a movie type",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID",
+                        "description": "Movie Type ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Movie information",
-                        "name": "movie",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/admin.createMovieRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Movie updated successfully",
+                        "description": "Movie type deleted successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1256,12 +1370,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.ApiError"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1271,9 +1379,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/recommendations": {
+        "/admin/movies": {
             "get": {
-                "description": "Retrieve all recommended movies ordered by their position",
+                "description": "Get a page of movies matching the filters, sorted by title, release_year or created_at, with the total count",
                 "consumes": [
                     "application/json"
                 ],
@@ -1281,21 +1389,146 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recommendations"
+                    "Movies"
+                ],
+                "summary": "Get all movies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated genre IDs",
+                        "name": "genreids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "any (default) or all",
+                        "name": "genrematch",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated category IDs",
+                        "name": "categoryids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "any (default) or all",
+                        "name": "categorymatch",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated movie type IDs",
+                        "name": "typeids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated age IDs",
+                        "name": "ageids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "any (default) or all",
+                        "name": "agematch",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum release year",
+                        "name": "yearfrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum release year",
+                        "name": "yearto",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum runtime in minutes",
+                        "name": "runtimefrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum runtime in minutes",
+                        "name": "runtimeto",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Director name substring",
+                        "name": "director",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Producer name substring",
+                        "name": "producer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated keywords",
+                        "name": "keywords",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "any (default) or all",
+                        "name": "keywordmatch",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated statuses: draft, scheduled, published, archived (default all)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort key: title, release_year, created_at (default)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: asc or desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all recommendations",
                 "responses": {
                     "200": {
-                        "description": "List of recommended movies",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.RecommendedMovie"
-                            }
+                            "$ref": "#/definitions/models.MoviesPage"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -1303,7 +1536,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Add a new movie to recommendations with a specified position",
+                "description": "Create a new movie with the specified information",
                 "consumes": [
                     "application/json"
                 ],
@@ -1311,23 +1544,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recommendations"
+                    "Movies"
                 ],
-                "summary": "Create a new recommendation",
+                "summary": "Create a new movie",
                 "parameters": [
                     {
-                        "description": "Recommendation Information",
-                        "name": "recommendation",
+                        "description": "Movie information",
+                        "name": "movie",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/admin.createRecommendationRequest"
+                            "$ref": "#/definitions/admin.createMovieRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Id of the created recommendation",
+                    "200": {
+                        "description": "Movie ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1336,13 +1569,19 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request format",
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -1350,9 +1589,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/recommendations/{id}": {
-            "get": {
-                "description": "Retrieve a recommendation by its Id",
+        "/admin/movies/{Id}/seasons": {
+            "post": {
+                "description": "Adds a new season with episodes to a movie",
                 "consumes": [
                     "application/json"
                 ],
@@ -1360,33 +1599,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recommendations"
+                    "content"
                 ],
-                "summary": "Get recommendation by Id",
+                "summary": "Add seasons and episodes to a movie",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Recommendation Id",
+                        "description": "Movie ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
+                    },
+                    {
+                        "description": "Season and episodes data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.CreateSeasonRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Recommended movie found",
+                        "description": "Seasons and episodes added successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.RecommendedMovie"
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid recommendation Id",
-                        "schema": {
-                            "$ref": "#/definitions/models.ApiError"
-                        }
-                    },
-                    "404": {
-                        "description": "Recommendation not found",
+                        "description": "Invalid movie id or payload",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -1398,9 +1645,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove a recommendation by its Id",
+            }
+        },
+        "/admin/movies/{Id}/seasons/{seasonId}": {
+            "put": {
+                "description": "Updates an existing season's details and episodes",
                 "consumes": [
                     "application/json"
                 ],
@@ -1408,73 +1657,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recommendations"
+                    "content"
                 ],
-                "summary": "Delete a recommendation",
+                "summary": "Update season details",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Recommendation Id",
-                        "name": "id",
+                        "description": "Movie ID",
+                        "name": "Id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Season ID",
+                        "name": "seasonId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Season and episode update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.UpdateSeasonRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Recommendation deleted successfully",
+                        "description": "Season and episodes updated successfully",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
                             }
                         }
                     },
                     "400": {
-                        "description": "Invalid recommendation Id",
+                        "description": "Invalid movie Id or seasonId or payload",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Season not found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
-                    }
-                }
-            }
-        },
-        "/admin/roles": {
-            "get": {
-                "description": "Get a list of all roles",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Roles"
-                ],
-                "summary": "Get all roles",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Role"
-                            }
-                        }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     }
                 }
             },
-            "post": {
-                "description": "Create a new role with specified permissions",
+            "delete": {
+                "description": "Removes a season and its episodes from a movie",
                 "consumes": [
                     "application/json"
                 ],
@@ -1482,35 +1726,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "content"
                 ],
-                "summary": "Create a new role",
+                "summary": "Delete a season from a movie",
                 "parameters": [
                     {
-                        "description": "Role data",
-                        "name": "role",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/admin.createRoleRequest"
-                        }
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "Id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Season ID",
+                        "name": "seasonId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "ID of the created role",
+                        "description": "Season deleted successfully",
                         "schema": {
-                            "type": "integer"
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid movie id or seasonId",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -1518,20 +1772,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/roles/{id}": {
+        "/admin/movies/{id}": {
             "get": {
-                "description": "Get the details of a specific role by ID",
+                "description": "Get a movie by its ID",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "Movies"
                 ],
-                "summary": "Get a role by ID",
+                "summary": "Get movie by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Role ID",
+                        "description": "Movie ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1541,7 +1798,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Role"
+                            "$ref": "#/definitions/models.Movie"
                         }
                     },
                     "400": {
@@ -1555,17 +1812,11 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/models.ApiError"
-                        }
                     }
                 }
             },
-            "put": {
-                "description": "Update an existing role with new information",
+            "delete": {
+                "description": "Delete a movie by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1573,32 +1824,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "Movies"
                 ],
-                "summary": "Update an existing role",
+                "summary": "Delete movie",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Role ID",
+                        "description": "Movie ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated role data",
-                        "name": "role",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Role"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Movie deleted successfully",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -1621,29 +1866,44 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete an existing role by ID",
+            "patch": {
+                "description": "Update an existing movie by its ID",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "Movies"
                 ],
-                "summary": "Delete a role",
+                "summary": "Update movie",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Role ID",
+                        "description": "Movie ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Movie information",
+                        "name": "movie",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.createMovieRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Movie updated successfully",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -1667,56 +1927,67 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/search": {
+        "/admin/movies/{id}/assets": {
             "get": {
-                "description": "Retrieve movies, users, and categories based on the search query",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Lists video assets with renditions, subtitles and audio tracks. The main video goes first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "search"
+                    "video assets"
                 ],
-                "summary": "Search for movies, users, and categories",
+                "summary": "Get videos of an episode or a movie",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Search query",
-                        "name": "q",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "Movie ID, for a movie without seasons",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of search results",
+                        "description": "Video assets",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/repositories.SearchResult"
+                                "$ref": "#/definitions/models.VideoAsset"
                             }
                         }
                     },
                     "400": {
-                        "description": "Invalid search query",
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Episode not found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Error during search",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     }
                 }
-            }
-        },
-        "/admin/seasons/{seasonId}/episodes/{episodeId}": {
-            "put": {
-                "description": "Updates an existing episode's details",
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Attaches a video to an episode or to a movie without seasons. An episode or movie has at most\none main video and any number of trailers. Needs hls_url or dash_url; subtitles are kk, ru or en,\none track per language. Audio tracks take any ISO 639 code (kk, tr, en-US), unique by language and label",
                 "consumes": [
                     "application/json"
                 ],
@@ -1724,47 +1995,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "content"
+                    "video assets"
                 ],
-                "summary": "Update episode details",
+                "summary": "Add a video",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Episode ID",
-                        "name": "episodeId",
+                        "description": "Movie ID, for a movie without seasons",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Episode update data",
-                        "name": "request",
+                        "description": "Video, renditions, subtitles and audio tracks",
+                        "name": "asset",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/admin.UpdateEpisodeRequest"
+                            "$ref": "#/definitions/admin.videoAssetRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Episode updated successfully",
+                    "201": {
+                        "description": "ID of the created video asset",
                         "schema": {
                             "type": "object",
-                            "properties": {
-                                "message": {
-                                    "type": "string"
-                                }
+                            "additionalProperties": {
+                                "type": "integer"
                             }
                         }
                     },
                     "400": {
-                        "description": "Invalid episodeId or payload",
+                        "description": "Invalid video or the movie has seasons",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "404": {
-                        "description": "Episode not found",
+                        "description": "Episode or movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "409": {
+                        "description": "Main video already exists",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -1776,90 +2051,72 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Removes an episode from a season",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/admin/movies/{id}/assets/{assetId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "content"
+                    "video assets"
                 ],
-                "summary": "Delete an episode",
+                "summary": "Get a video",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Episode ID",
-                        "name": "episodeId",
+                        "description": "Movie ID, for a movie without seasons",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Video asset ID",
+                        "name": "assetId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Episode deleted successfully",
+                        "description": "Video asset",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "message": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/models.VideoAsset"
                         }
                     },
                     "400": {
-                        "description": "Invalid episodeId",
+                        "description": "Invalid ID",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Episode or video not found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
-                    }
-                }
-            }
-        },
-        "/admin/users": {
-            "get": {
-                "description": "Retrieve a list of all users",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "Get all users",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/admin.userResponse"
-                            }
-                        }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     }
                 }
-            }
-        },
-        "/admin/users/{id}": {
-            "get": {
-                "description": "Retrieve a user by their ID",
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replaces the video and all of its renditions, subtitles and audio tracks",
                 "consumes": [
                     "application/json"
                 ],
@@ -1867,39 +2124,58 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "video assets"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Replace a video",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Movie ID, for a movie without seasons",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Video asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Video, renditions, subtitles and audio tracks",
+                        "name": "asset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.videoAssetRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.User"
-                        }
+                        "description": "Video updated"
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid video",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Episode or video not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "409": {
+                        "description": "Main video already exists",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -1907,41 +2183,58 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a user by their ID",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "video assets"
                 ],
-                "summary": "Delete a user by ID",
+                "summary": "Delete a video",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Movie ID, for a movie without seasons",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Video asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User deleted successfully",
+                        "description": "Video deleted successfully",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Episode or video not found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -1949,9 +2242,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/users/{id}/getRole": {
+        "/admin/movies/{id}/status": {
             "put": {
-                "description": "Assign a role to a user by their ID",
+                "description": "Move a movie between draft, scheduled, published and archived.\nscheduled requires publish_at in the future; published and scheduled accept an optional unpublish_at.\nOnly published movies are visible in public endpoints",
                 "consumes": [
                     "application/json"
                 ],
@@ -1959,33 +2252,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Movies"
                 ],
-                "summary": "Assign a role to a user",
+                "summary": "Change movie status",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Movie ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Role ID",
-                        "name": "role",
+                        "description": "New status and schedule",
+                        "name": "status",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/admin.AssignRoleRequest"
+                            "$ref": "#/definitions/admin.changeMovieStatusRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Role assigned successfully",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Movie"
                         }
                     },
                     "400": {
@@ -1994,6 +2286,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2003,56 +2301,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/signIn": {
-            "post": {
-                "description": "Authenticates a user by verifying the email and password, and returns a JWT token",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/permissions": {
+            "get": {
+                "description": "Get the list of permissions that can be assigned to roles",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
-                ],
-                "summary": "User Sign In",
-                "parameters": [
-                    {
-                        "description": "User sign-in request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/public.SignInRequest"
-                        }
-                    }
+                    "Roles"
                 ],
+                "summary": "Get all permissions",
                 "responses": {
                     "200": {
-                        "description": "JWT token successfully generated",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "token": {
-                                    "type": "string"
-                                }
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Permission"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid payload",
-                        "schema": {
-                            "$ref": "#/definitions/models.ApiError"
-                        }
-                    },
-                    "401": {
-                        "description": "Invalid credentials: wrong email or password",
-                        "schema": {
-                            "$ref": "#/definitions/models.ApiError"
-                        }
-                    },
                     "500": {
-                        "description": "Internal server error: failed to generate JWT token",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -2060,14 +2330,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/signOut": {
-            "post": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Invalidates the user's current session (requires a valid JWT token)",
+        "/admin/recommendations": {
+            "get": {
+                "description": "Retrieve all recommended movies ordered by their position",
                 "consumes": [
                     "application/json"
                 ],
@@ -2075,25 +2340,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "recommendations"
                 ],
-                "summary": "User Sign Out",
+                "summary": "Get all recommendations",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "List of recommended movies",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RecommendedMovie"
+                            }
+                        }
                     },
-                    "401": {
-                        "description": "Authorization header required",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     }
                 }
-            }
-        },
-        "/auth/signUp": {
+            },
             "post": {
-                "description": "Registers a new user by providing an email, password, and password confirmation",
+                "description": "Add a new movie to recommendations with a specified position",
                 "consumes": [
                     "application/json"
                 ],
@@ -2101,40 +2370,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "recommendations"
                 ],
-                "summary": "User Registration",
+                "summary": "Create a new recommendation",
                 "parameters": [
                     {
-                        "description": "User registration request",
-                        "name": "request",
+                        "description": "Recommendation Information",
+                        "name": "recommendation",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/public.SignUpRequest"
+                            "$ref": "#/definitions/admin.createRecommendationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "User successfully created",
+                    "201": {
+                        "description": "Id of the created recommendation",
                         "schema": {
                             "type": "object",
-                            "properties": {
-                                "id": {
-                                    "type": "integer"
-                                }
+                            "additionalProperties": {
+                                "type": "integer"
                             }
                         }
                     },
                     "400": {
-                        "description": "Validation error: invalid email, password mismatch, or weak password",
+                        "description": "Invalid request format",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Server error: failed to hash password or create user",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -2142,24 +2409,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories": {
+        "/admin/recommendations/{id}": {
             "get": {
-                "description": "Retrieves a list of all categories",
+                "description": "Retrieve a recommendation by its Id",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "recommendations"
+                ],
+                "summary": "Get recommendation by Id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recommendation Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all categories",
                 "responses": {
                     "200": {
-                        "description": "A list of categories",
+                        "description": "Recommended movie found",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Category"
-                            }
+                            "$ref": "#/definitions/models.RecommendedMovie"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid recommendation Id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Recommendation not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
@@ -2169,11 +2457,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/homepage": {
-            "get": {
-                "description": "Retrieves the main screen data, including recommended movies, categories, genres, and age ratings",
+            },
+            "delete": {
+                "description": "Remove a recommendation by its Id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2181,18 +2467,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "homepage"
+                    "recommendations"
+                ],
+                "summary": "Delete a recommendation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recommendation Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get Main Screen Data",
                 "responses": {
                     "200": {
-                        "description": "Main screen data including recommended movies, categories, genres, and ages",
+                        "description": "Recommendation deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/gin.H"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid recommendation Id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Server error: failed to load data",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -2200,9 +2504,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/profile/changepassword/{id}": {
-            "put": {
-                "description": "Allows a user to change their password",
+        "/admin/roles": {
+            "get": {
+                "description": "Get a list of all roles",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Get all roles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Role"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new role with the specified set of permissions, e.g. \"movies:write\"",
                 "consumes": [
                     "application/json"
                 ],
@@ -2210,48 +2541,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Public Profile"
+                    "Roles"
                 ],
-                "summary": "Change user password",
+                "summary": "Create a new role",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Password Reset Data",
-                        "name": "body",
+                        "description": "Role data",
+                        "name": "role",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/public.ResetPasswordRequest"
+                            "$ref": "#/definitions/admin.createRoleRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Password changed successfully",
+                        "description": "ID of the created role",
                         "schema": {
-                            "type": "string"
+                            "type": "integer"
                         }
                     },
                     "400": {
-                        "description": "Invalid input data",
-                        "schema": {
-                            "$ref": "#/definitions/models.ApiError"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -2259,23 +2577,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/profile/{id}": {
+        "/admin/roles/{id}": {
             "get": {
-                "description": "Retrieves a user profile by ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get the details of a specific role by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Public Profile"
+                    "Roles"
                 ],
-                "summary": "Get user profile",
+                "summary": "Get a role by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Role ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2285,17 +2600,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/public.profileResponse"
+                            "$ref": "#/definitions/models.Role"
                         }
                     },
                     "400": {
-                        "description": "Invalid user id",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -2303,7 +2624,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Updates the details of a user profile by ID",
+                "description": "Update an existing role's name and replace its set of permissions",
                 "consumes": [
                     "application/json"
                 ],
@@ -2311,86 +2632,93 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Public Profile"
+                    "Roles"
                 ],
-                "summary": "Update user profile",
+                "summary": "Update an existing role",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Role ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update Profile Data",
-                        "name": "body",
+                        "description": "Updated role data",
+                        "name": "role",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/public.updateRequest"
+                            "$ref": "#/definitions/admin.createRoleRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile updated successfully",
+                        "description": "OK",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid input data",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     }
                 }
-            }
-        },
-        "/search": {
-            "get": {
-                "description": "Searches for movies based on a query string",
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Delete an existing role by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "homepage"
+                    "Roles"
                 ],
-                "summary": "Search Movies",
+                "summary": "Delete a role",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Search query",
-                        "name": "query",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Search results with matching movies",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/gin.H"
+                            "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Bad request: empty search query",
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Server error: failed to search movies",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -2398,9 +2726,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/search/{category_id}": {
+        "/admin/search": {
             "get": {
-                "description": "Retrieves movies based on the provided category ID",
+                "description": "Search movies, seasons, episodes, categories, genres, ages, movie types and users.\nReturns up to limit results per type, links to the matching /admin routes and total match counts per type",
                 "consumes": [
                     "application/json"
                 ],
@@ -2408,33 +2736,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "homepage"
+                    "search"
                 ],
-                "summary": "Get Movies by Category",
+                "summary": "Search across all admin entities",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Category ID",
-                        "name": "category_id",
-                        "in": "path",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated types: Movie, Season, Episode, Category, Genre, Age, MovieType, User (default all)",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Results per type, 1-50 (default 10)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Movies belonging to the specified category",
+                        "description": "Search results and facet counts",
                         "schema": {
-                            "$ref": "#/definitions/gin.H"
+                            "$ref": "#/definitions/repositories.SearchAllResult"
                         }
                     },
                     "400": {
-                        "description": "Bad request: category ID is required",
+                        "description": "Invalid search query",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Server error: failed to load movies for category",
+                        "description": "Error during search",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
@@ -2442,9 +2782,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/watchlist": {
-            "get": {
-                "description": "Retrieves the list of movies in the user's watchlist",
+        "/admin/seasons/{seasonId}/episodes/{episodeId}": {
+            "put": {
+                "description": "Updates an existing episode's details",
                 "consumes": [
                     "application/json"
                 ],
@@ -2452,31 +2792,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "watchlist"
+                    "content"
                 ],
-                "summary": "Get user's watchlist",
-                "responses": {
-                    "200": {
-                        "description": "List of movies in the watchlist",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Movie"
+                "summary": "Update episode details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Episode ID",
+                        "name": "episodeId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Episode update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.UpdateEpisodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Episode updated successfully",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
                             }
                         }
                     },
+                    "400": {
+                        "description": "Invalid episodeId or payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Episode not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to get watchlist",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     }
                 }
-            }
-        },
-        "/watchlist/{movie_id}": {
-            "get": {
-                "description": "Checks if a movie is in the user's watchlist",
+            },
+            "delete": {
+                "description": "Removes an episode from a season",
                 "consumes": [
                     "application/json"
                 ],
@@ -2484,83 +2854,113 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "watchlist"
+                    "content"
                 ],
-                "summary": "Check if movie is in watchlist",
+                "summary": "Delete an episode",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID to check",
-                        "name": "movie_id",
+                        "description": "Episode ID",
+                        "name": "episodeId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success status (true if movie is in watchlist)",
+                        "description": "Episode deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/public.watchlistResponse"
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid movie ID",
+                        "description": "Invalid episodeId",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Failed to check watchlist",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Adds a movie to the user's watchlist",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/admin/seasons/{seasonId}/episodes/{episodeId}/assets": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Lists video assets with renditions, subtitles and audio tracks. The main video goes first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "watchlist"
+                    "video assets"
                 ],
-                "summary": "Add movie to watchlist",
+                "summary": "Get videos of an episode or a movie",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID to add",
-                        "name": "movie_id",
+                        "description": "Season ID",
+                        "name": "seasonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Episode ID",
+                        "name": "episodeId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Movie successfully added to the watchlist",
+                        "description": "Video assets",
                         "schema": {
-                            "$ref": "#/definitions/public.watchlistResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.VideoAsset"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid movie ID",
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Episode not found",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Failed to add to watchlist",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     }
                 }
             },
-            "delete": {
-                "description": "Removes a movie from the user's watchlist",
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Attaches a video to an episode or to a movie without seasons. An episode or movie has at most\none main video and any number of trailers. Needs hls_url or dash_url; subtitles are kk, ru or en,\none track per language. Audio tracks take any ISO 639 code (kk, tr, en-US), unique by language and label",
                 "consumes": [
                     "application/json"
                 ],
@@ -2568,149 +2968,2410 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "watchlist"
+                    "video assets"
                 ],
-                "summary": "Remove movie from watchlist",
+                "summary": "Add a video",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID to remove",
-                        "name": "movie_id",
+                        "description": "Season ID",
+                        "name": "seasonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Episode ID",
+                        "name": "episodeId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Video, renditions, subtitles and audio tracks",
+                        "name": "asset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.videoAssetRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Movie successfully removed from the watchlist",
+                    "201": {
+                        "description": "ID of the created video asset",
                         "schema": {
-                            "$ref": "#/definitions/public.watchlistResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid movie ID",
+                        "description": "Invalid video or the movie has seasons",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Episode or movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "409": {
+                        "description": "Main video already exists",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     },
                     "500": {
-                        "description": "Failed to remove from watchlist",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/models.ApiError"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "admin.AssignRoleRequest": {
-            "type": "object",
-            "required": [
-                "role_id"
-            ],
-            "properties": {
-                "role_id": {
-                    "type": "integer"
-                }
-            }
         },
-        "admin.CreateEpisodeRequest": {
-            "type": "object",
-            "properties": {
-                "number": {
+        "/admin/seasons/{seasonId}/episodes/{episodeId}/assets/{assetId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video assets"
+                ],
+                "summary": "Get a video",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Season ID",
+                        "name": "seasonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Episode ID",
+                        "name": "episodeId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Video asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Video asset",
+                        "schema": {
+                            "$ref": "#/definitions/models.VideoAsset"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Episode or video not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replaces the video and all of its renditions, subtitles and audio tracks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video assets"
+                ],
+                "summary": "Replace a video",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Season ID",
+                        "name": "seasonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Episode ID",
+                        "name": "episodeId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Video asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Video, renditions, subtitles and audio tracks",
+                        "name": "asset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.videoAssetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Video updated"
+                    },
+                    "400": {
+                        "description": "Invalid video",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Episode or video not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "409": {
+                        "description": "Main video already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "video assets"
+                ],
+                "summary": "Delete a video",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Season ID",
+                        "name": "seasonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Episode ID",
+                        "name": "episodeId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Video asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Video deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Episode or video not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "description": "Retrieve a list of all users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/admin.userResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}": {
+            "get": {
+                "description": "Retrieve a user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete a user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User deleted successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/getRole": {
+            "put": {
+                "description": "Assign a role to a user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Assign a role to a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role ID",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.AssignRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role assigned successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token for a new access token and a new refresh token. Each refresh token can be used once; reusing one revokes every token issued from the same sign-in",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh Tokens",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/public.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New access and refresh tokens",
+                        "schema": {
+                            "$ref": "#/definitions/public.tokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "401": {
+                        "description": "Refresh token is invalid, expired or reused",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/signIn": {
+            "post": {
+                "description": "Authenticates a user by verifying the email and password, and returns a short-lived JWT access token with a refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User Sign In",
+                "parameters": [
+                    {
+                        "description": "User sign-in request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/public.SignInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "JWT and refresh tokens successfully generated",
+                        "schema": {
+                            "$ref": "#/definitions/public.tokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials: wrong email or password",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error: failed to generate JWT token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/signOut": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revokes the JWT token used for the request so it can no longer be used, even before it expires. If a refresh token is passed, every refresh token issued from the same sign-in is revoked too",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User Sign Out",
+                "parameters": [
+                    {
+                        "description": "Refresh token to revoke",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/public.SignOutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Authorization header required",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/signUp": {
+            "post": {
+                "description": "Registers a new user by providing an email, password, and password confirmation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User Registration",
+                "parameters": [
+                    {
+                        "description": "User registration request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/public.SignUpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User successfully created, access and refresh tokens issued",
+                        "schema": {
+                            "$ref": "#/definitions/public.tokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error: invalid email, password mismatch, or weak password",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error: failed to hash password or create user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "description": "Retrieves a list of all categories",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Get all categories",
+                "responses": {
+                    "200": {
+                        "description": "A list of categories",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Category"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/homepage": {
+            "get": {
+                "description": "Retrieves the main screen: rails configured under /admin/homepage in their order, genres and age ratings.\nRails contain lightweight movie cards; empty rails and rails outside their visibility window are omitted.\nWithout configured rails the default layout is returned: recommended movies and the newest movies of every category.\nA section that fails to load is left out and listed in unavailable_sections",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "homepage"
+                ],
+                "summary": "Get Main Screen Data",
+                "responses": {
+                    "200": {
+                        "description": "Main screen data including rails, genres, and ages",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error: failed to load data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/{key}": {
+            "get": {
+                "description": "Serves an uploaded cover, screenshot or poster with ETag, Cache-Control and Range support.\nWith w and/or format returns a downscaled copy, generated on the first request and cached on disk",
+                "produces": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/webp"
+                ],
+                "tags": [
+                    "media"
+                ],
+                "summary": "Get a media file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Storage key",
+                        "name": "key",
+                        "in": "path"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Width: 160, 320, 480, 640, 960, 1280 or 1920. Images are never upscaled",
+                        "name": "w",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "jpeg, png or webp (default: format of the original)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Media file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Requested range",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Invalid w or format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Media not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "415": {
+                        "description": "Media is not an image that can be resized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to load media",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns a published movie with genres, categories, ages, media URLs and seasons with episodes sorted\nby number. Videos with renditions, subtitles and audio tracks are listed in assets of the movie\n(movies without seasons) or of each episode. Also tells whether the movie is in the user's watchlist,\nwhat the user has watched and lists related titles that share genres or categories",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Get movie details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Movie details",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to load movie",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/changepassword/{id}": {
+            "put": {
+                "description": "Changes the password of the token owner. Other users' passwords require the users:write permission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public Profile"
+                ],
+                "summary": "Change user password",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Password Reset Data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/public.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password changed successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "403": {
+                        "description": "Access to another user's profile is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/me": {
+            "get": {
+                "description": "Retrieves the profile of the token owner. Other users' profiles require the users:read permission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public Profile"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/public.profileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "403": {
+                        "description": "Access to another user's profile is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the profile of the token owner. Other users' profiles require the users:write permission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public Profile"
+                ],
+                "summary": "Update user profile",
+                "parameters": [
+                    {
+                        "description": "Update Profile Data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/public.updateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "403": {
+                        "description": "Access to another user's profile is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/me/password": {
+            "put": {
+                "description": "Changes the password of the token owner. Other users' passwords require the users:write permission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public Profile"
+                ],
+                "summary": "Change user password",
+                "parameters": [
+                    {
+                        "description": "Password Reset Data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/public.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password changed successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "403": {
+                        "description": "Access to another user's profile is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/{id}": {
+            "get": {
+                "description": "Retrieves the profile of the token owner. Other users' profiles require the users:read permission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public Profile"
+                ],
+                "summary": "Get user profile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/public.profileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "403": {
+                        "description": "Access to another user's profile is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the profile of the token owner. Other users' profiles require the users:write permission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public Profile"
+                ],
+                "summary": "Update user profile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Profile Data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/public.updateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "403": {
+                        "description": "Access to another user's profile is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/continue-watching": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Started and unfinished movies, most recently watched first. Series point at the episode to play next",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "progress"
+                ],
+                "summary": "Continue watching",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of movies, 1-50 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Movies to continue",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/public.ContinueWatchingItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to load continue watching",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/episodes/{episode_id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Saves the playback position of a series episode. The episode is marked watched once the position\npasses 90% of the duration, or explicitly with \"watched\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "progress"
+                ],
+                "summary": "Report episode playback position",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Episode ID",
+                        "name": "episode_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Playback position",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaybackReport"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated watch state of the series",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieWatchState"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Episode not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save progress",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/movies/{movie_id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns watched flags and playback positions of the movie and, for series, of every episode in order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "progress"
+                ],
+                "summary": "Get watch state of a movie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Watch state",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieWatchState"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to load progress",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Saves the playback position of a single film. The film is marked watched once the position\npasses 90% of the duration, or explicitly with \"watched\". Series report progress per episode",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "progress"
+                ],
+                "summary": "Report movie playback position",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Playback position",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaybackReport"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated watch state",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieWatchState"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data or the movie is a series",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save progress",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Removes playback positions and watched flags of the movie and all of its episodes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "progress"
+                ],
+                "summary": "Mark a movie as unwatched",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cleared watch state",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieWatchState"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to reset progress",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/movies/{movie_id}/next": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Resolves the episode to continue a series with by season and episode order: the first episode\nwhen nothing was watched, the unfinished episode with its position, or the one after the last watched",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "progress"
+                ],
+                "summary": "Get the episode to play next",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Episode to play",
+                        "schema": {
+                            "$ref": "#/definitions/models.NextEpisode"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found, not a series or already watched to the end",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to resolve next episode",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Full-text search over title, keywords, director, producer and description (Kazakh, Russian, English).\nWords match by prefix, typos in the title are tolerated, results are ordered by relevance\nand carry highlighted snippets with matches wrapped in \u003cb\u003e\u003c/b\u003e",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "homepage"
+                ],
+                "summary": "Search Movies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "query",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Search results with matching movies",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request: empty search query or invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error: failed to search movies",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/search/suggest": {
+            "get": {
+                "description": "Returns typeahead completions for a prefix: movie titles, keywords, people (directors and producers),\ncategories and genres. Up to limit suggestions of each type, most popular first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search suggestions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Prefix typed by the user",
+                        "name": "query",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Suggestions per type, 1-20 (default 5)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Suggestions",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request: empty query or invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error: failed to load suggestions",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/search/{category_id}": {
+            "get": {
+                "description": "Retrieves a page of movies for the provided category ID with the total count",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "homepage"
+                ],
+                "summary": "Get Movies by Category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort key: title, release_year, created_at (default)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: asc or desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Movie cards belonging to the specified category",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieCardsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request: invalid category ID or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error: failed to load movies for category",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist": {
+            "get": {
+                "description": "Retrieves the list of movies in the user's watchlist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Get user's watchlist",
+                "responses": {
+                    "200": {
+                        "description": "List of movies in the watchlist",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Movie"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get watchlist",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist/{movie_id}": {
+            "get": {
+                "description": "Checks if a movie is in the user's watchlist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Check if movie is in watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID to check",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success status (true if movie is in watchlist)",
+                        "schema": {
+                            "$ref": "#/definitions/public.watchlistResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to check watchlist",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a movie to the user's watchlist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Add movie to watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID to add",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Movie successfully added to the watchlist",
+                        "schema": {
+                            "$ref": "#/definitions/public.watchlistResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to add to watchlist",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a movie from the user's watchlist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Remove movie from watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID to remove",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Movie successfully removed from the watchlist",
+                        "schema": {
+                            "$ref": "#/definitions/public.watchlistResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to remove from watchlist",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "admin.AssignRoleRequest": {
+            "type": "object",
+            "required": [
+                "role_id"
+            ],
+            "properties": {
+                "role_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin.CreateEpisodeRequest": {
+            "type": "object",
+            "properties": {
+                "number": {
+                    "type": "integer"
+                },
+                "seasonId": {
+                    "type": "integer"
+                },
+                "videoURL": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.CreateSeasonRequest": {
+            "type": "object",
+            "properties": {
+                "episodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.CreateEpisodeRequest"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin.UpdateEpisodeRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "seasonId": {
+                    "type": "integer"
+                },
+                "videoURL": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.UpdateSeasonRequest": {
+            "type": "object",
+            "properties": {
+                "episodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.UpdateEpisodeRequest"
+                    }
+                },
+                "number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin.changeMovieStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "publish_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "unpublish_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.createAgesRequest": {
+            "type": "object",
+            "properties": {
+                "poster": {
+                    "$ref": "#/definitions/multipart.FileHeader"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.createCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.createMovieRequest": {
+            "type": "object",
+            "properties": {
+                "ages": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "director": {
+                    "type": "string"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "movieTypeId": {
+                    "type": "integer"
+                },
+                "producer": {
+                    "type": "string"
+                },
+                "releaseYear": {
+                    "type": "integer"
+                },
+                "runtime": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.createMovieTypeRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.createRecommendationRequest": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin.createRoleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "admin.homepageRailRequest": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "genre_id": {
+                    "type": "integer"
+                },
+                "movie_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "visible_from": {
+                    "type": "string"
+                },
+                "visible_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.reorderMediaRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "admin.reorderRailsRequest": {
+            "type": "object",
+            "required": [
+                "rail_ids"
+            ],
+            "properties": {
+                "rail_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "admin.userResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.videoAssetRequest": {
+            "type": "object",
+            "properties": {
+                "audio_tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AudioTrack"
+                    }
+                },
+                "dash_url": {
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "type": "integer"
+                },
+                "hls_url": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "renditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VideoRendition"
+                    }
+                },
+                "subtitles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubtitleTrack"
+                    }
+                }
+            }
+        },
+        "gin.H": {
+            "type": "object",
+            "additionalProperties": {}
+        },
+        "models.Ages": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "posterUrl": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ApiError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AudioTrack": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "type": "integer"
+                },
+                "codec": {
+                    "type": "string"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "language": {
+                    "description": "код ISO 639: kk, ru, tr, en-US",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Episode": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "seasonID": {
+                    "type": "integer"
+                },
+                "videoURL": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EpisodeDetail": {
+            "type": "object",
+            "properties": {
+                "assets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VideoAsset"
+                    }
+                },
+                "duration_seconds": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "position_seconds": {
+                    "type": "integer"
+                },
+                "video_url": {
+                    "type": "string"
+                },
+                "watched": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.EpisodeWatchState": {
+            "type": "object",
+            "properties": {
+                "duration_seconds": {
+                    "type": "integer"
+                },
+                "episode_id": {
+                    "type": "integer"
+                },
+                "episode_number": {
+                    "type": "integer"
+                },
+                "position_seconds": {
+                    "type": "integer"
+                },
+                "season_id": {
+                    "type": "integer"
+                },
+                "season_number": {
+                    "type": "integer"
+                },
+                "watched": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Genre": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "posterUrl": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HomepageRail": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "genre_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "movie_ids": {
+                    "description": "Только для hand_picked",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "position": {
                     "type": "integer"
                 },
-                "seasonId": {
+                "size": {
                     "type": "integer"
                 },
-                "videoURL": {
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "visible_from": {
+                    "type": "string"
+                },
+                "visible_until": {
                     "type": "string"
                 }
             }
         },
-        "admin.CreateSeasonRequest": {
+        "models.ImageRule": {
             "type": "object",
             "properties": {
-                "episodes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/admin.CreateEpisodeRequest"
-                    }
+                "max_bytes": {
+                    "type": "integer"
                 },
-                "id": {
+                "max_height": {
                     "type": "integer"
                 },
-                "number": {
+                "max_width": {
+                    "type": "integer"
+                },
+                "min_height": {
+                    "type": "integer"
+                },
+                "min_width": {
                     "type": "integer"
                 }
             }
         },
-        "admin.UpdateEpisodeRequest": {
+        "models.MediaGCReport": {
             "type": "object",
             "properties": {
-                "id": {
+                "deleted": {
                     "type": "integer"
                 },
-                "number": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
                     "type": "integer"
                 },
-                "seasonId": {
+                "freed_bytes": {
                     "type": "integer"
                 },
-                "videoURL": {
+                "grace_period": {
                     "type": "string"
-                }
-            }
-        },
-        "admin.UpdateSeasonRequest": {
-            "type": "object",
-            "properties": {
-                "episodes": {
+                },
+                "orphans": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/admin.UpdateEpisodeRequest"
+                        "$ref": "#/definitions/models.OrphanedMedia"
                     }
                 },
-                "number": {
+                "recent": {
+                    "description": "не используются, но моложе grace_period",
                     "type": "integer"
-                }
-            }
-        },
-        "admin.createAgesRequest": {
-            "type": "object",
-            "properties": {
-                "poster": {
-                    "$ref": "#/definitions/multipart.FileHeader"
                 },
-                "title": {
-                    "type": "string"
-                }
-            }
-        },
-        "admin.createCategoryRequest": {
-            "type": "object",
-            "properties": {
-                "title": {
+                "referenced": {
+                    "description": "из них используются фильмами, жанрами или возрастами",
+                    "type": "integer"
+                },
+                "scanned": {
+                    "description": "объектов в хранилище",
+                    "type": "integer"
+                },
+                "started_at": {
                     "type": "string"
                 }
             }
         },
-        "admin.createMovieRequest": {
+        "models.Movie": {
             "type": "object",
             "properties": {
                 "ages": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/models.Ages"
                     }
                 },
                 "categories": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/models.Category"
                     }
                 },
+                "createdAt": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -2720,249 +5381,387 @@ const docTemplate = `{
                 "genres": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/models.Genre"
                     }
                 },
-                "keywords": {
+                "id": {
+                    "type": "integer"
+                },
+                "keyWords": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "media": {
+                    "$ref": "#/definitions/models.MovieMedia"
+                },
+                "movieType": {
+                    "type": "string"
+                },
                 "movieTypeId": {
                     "type": "integer"
                 },
                 "producer": {
                     "type": "string"
                 },
+                "publishAt": {
+                    "type": "string"
+                },
                 "releaseYear": {
                     "type": "integer"
                 },
                 "runtime": {
                     "type": "integer"
                 },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Season"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
+                },
+                "unpublishAt": {
+                    "type": "string"
                 }
             }
         },
-        "admin.createMovieTypeRequest": {
+        "models.MovieCard": {
             "type": "object",
             "properties": {
-                "name": {
+                "cover": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "movie_type": {
+                    "type": "string"
+                },
+                "movie_type_id": {
+                    "type": "integer"
+                },
+                "release_year": {
+                    "type": "integer"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "admin.createRecommendationRequest": {
+        "models.MovieCardsPage": {
             "type": "object",
             "properties": {
-                "movie_id": {
-                    "type": "integer"
+                "movies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MovieCard"
+                    }
                 },
-                "position": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "admin.createRoleRequest": {
+        "models.MovieDetail": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "can_edit_ages": {
-                    "type": "boolean"
-                },
-                "can_edit_categories": {
-                    "type": "boolean"
-                },
-                "can_edit_genres": {
-                    "type": "boolean"
+                "ages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NamedRef"
+                    }
                 },
-                "can_edit_projects": {
-                    "type": "boolean"
+                "assets": {
+                    "description": "видео фильма без сезонов; у сериала — пустой список",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VideoAsset"
+                    }
                 },
-                "can_edit_roles": {
-                    "type": "boolean"
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NamedRef"
+                    }
                 },
-                "can_edit_users": {
-                    "type": "boolean"
+                "cover": {
+                    "type": "string"
                 },
-                "name": {
+                "description": {
                     "type": "string"
-                }
-            }
-        },
-        "admin.userResponse": {
-            "type": "object",
-            "properties": {
-                "email": {
+                },
+                "director": {
                     "type": "string"
                 },
-                "id": {
+                "duration_seconds": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "gin.H": {
-            "type": "object",
-            "additionalProperties": {}
-        },
-        "models.Ages": {
-            "type": "object",
-            "properties": {
+                "gallery": {
+                    "description": "скриншоты с подписями, главный кадр отмечен is_hero",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MovieMediaItem"
+                    }
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NamedRef"
+                    }
+                },
                 "id": {
                     "type": "integer"
                 },
-                "posterUrl": {
+                "in_watchlist": {
+                    "type": "boolean"
+                },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "movie_type": {
+                    "$ref": "#/definitions/models.NamedRef"
+                },
+                "position_seconds": {
+                    "description": "только для полнометражных фильмов",
+                    "type": "integer"
+                },
+                "producer": {
                     "type": "string"
                 },
+                "related": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MovieCard"
+                    }
+                },
+                "release_year": {
+                    "type": "integer"
+                },
+                "runtime": {
+                    "type": "integer"
+                },
+                "screenshots": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SeasonDetail"
+                    }
+                },
                 "title": {
                     "type": "string"
+                },
+                "watched": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.ApiError": {
+        "models.MovieMedia": {
             "type": "object",
             "properties": {
-                "error": {
+                "cover": {
                     "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MovieMediaItem"
+                    }
+                },
+                "screenshots": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "models.Category": {
+        "models.MovieMediaItem": {
             "type": "object",
             "properties": {
+                "alt_text": {
+                    "type": "string"
+                },
+                "caption": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "title": {
+                "is_hero": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "models.Episode": {
+        "models.MovieMediaPatch": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
-                },
-                "number": {
-                    "type": "integer"
-                },
-                "seasonID": {
-                    "type": "integer"
+                "alt_text": {
+                    "type": "string"
                 },
-                "videoURL": {
+                "caption": {
                     "type": "string"
+                },
+                "is_hero": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.Genre": {
+        "models.MovieType": {
             "type": "object",
             "properties": {
                 "id": {
                     "type": "integer"
                 },
-                "posterUrl": {
-                    "type": "string"
-                },
                 "title": {
                     "type": "string"
                 }
             }
         },
-        "models.Movie": {
+        "models.MovieWatchState": {
             "type": "object",
             "properties": {
-                "ages": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Ages"
-                    }
+                "duration_seconds": {
+                    "type": "integer"
                 },
-                "categories": {
+                "episodes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Category"
+                        "$ref": "#/definitions/models.EpisodeWatchState"
                     }
                 },
-                "description": {
-                    "type": "string"
+                "movie_id": {
+                    "type": "integer"
                 },
-                "director": {
-                    "type": "string"
+                "position_seconds": {
+                    "type": "integer"
                 },
-                "genres": {
+                "watched": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MoviesPage": {
+            "type": "object",
+            "properties": {
+                "movies": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Genre"
+                        "$ref": "#/definitions/models.Movie"
                     }
                 },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NamedRef": {
+            "type": "object",
+            "properties": {
                 "id": {
                     "type": "integer"
                 },
-                "keyWords": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NextEpisode": {
+            "type": "object",
+            "properties": {
+                "duration_seconds": {
+                    "type": "integer"
                 },
-                "media": {
-                    "$ref": "#/definitions/models.MovieMedia"
+                "episode_id": {
+                    "type": "integer"
                 },
-                "movieType": {
-                    "type": "string"
+                "episode_number": {
+                    "type": "integer"
                 },
-                "movieTypeId": {
+                "position_seconds": {
                     "type": "integer"
                 },
-                "producer": {
-                    "type": "string"
+                "resume": {
+                    "type": "boolean"
                 },
-                "releaseYear": {
+                "season_id": {
                     "type": "integer"
                 },
-                "runtime": {
+                "season_number": {
                     "type": "integer"
+                }
+            }
+        },
+        "models.OrphanedMedia": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
                 },
-                "seasons": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Season"
-                    }
+                "key": {
+                    "type": "string"
                 },
-                "title": {
+                "modified_at": {
                     "type": "string"
+                },
+                "size": {
+                    "type": "integer"
                 }
             }
         },
-        "models.MovieMedia": {
+        "models.Permission": {
             "type": "object",
             "properties": {
-                "cover": {
+                "description": {
                     "type": "string"
                 },
-                "screenshots": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "models.MovieType": {
+        "models.PlaybackReport": {
             "type": "object",
             "properties": {
-                "id": {
+                "duration_seconds": {
                     "type": "integer"
                 },
-                "title": {
-                    "type": "string"
+                "position_seconds": {
+                    "type": "integer"
+                },
+                "watched": {
+                    "type": "boolean"
                 }
             }
         },
@@ -2983,29 +5782,18 @@ const docTemplate = `{
         "models.Role": {
             "type": "object",
             "properties": {
-                "canEditAges": {
-                    "type": "boolean"
-                },
-                "canEditCategories": {
-                    "type": "boolean"
-                },
-                "canEditGenres": {
-                    "type": "boolean"
-                },
-                "canEditProjects": {
-                    "type": "boolean"
-                },
-                "canEditRoles": {
-                    "type": "boolean"
-                },
-                "canEditUsers": {
-                    "type": "boolean"
-                },
                 "id": {
                     "type": "integer"
                 },
                 "name": {
                     "type": "string"
+                },
+                "permissions": {
+                    "description": "Названия прав, например \"movies:write\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -3032,6 +5820,65 @@ const docTemplate = `{
                 }
             }
         },
+        "models.SeasonDetail": {
+            "type": "object",
+            "properties": {
+                "episodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EpisodeDetail"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SubtitleTrack": {
+            "type": "object",
+            "properties": {
+                "format": {
+                    "description": "vtt или srt",
+                    "type": "string"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "language": {
+                    "description": "kk, ru или en",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UploadError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "rule": {
+                    "$ref": "#/definitions/models.ImageRule"
+                }
+            }
+        },
         "models.User": {
             "type": "object",
             "properties": {
@@ -3058,6 +5905,76 @@ const docTemplate = `{
                 }
             }
         },
+        "models.VideoAsset": {
+            "type": "object",
+            "properties": {
+                "audio_tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AudioTrack"
+                    }
+                },
+                "dash_url": {
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "type": "integer"
+                },
+                "episode_id": {
+                    "type": "integer"
+                },
+                "hls_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "integer"
+                },
+                "renditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VideoRendition"
+                    }
+                },
+                "subtitles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubtitleTrack"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.VideoRendition": {
+            "type": "object",
+            "properties": {
+                "bitrate_kbps": {
+                    "type": "integer"
+                },
+                "codec": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "quality": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
         "multipart.FileHeader": {
             "type": "object",
             "properties": {
@@ -3072,6 +5989,34 @@ const docTemplate = `{
                 }
             }
         },
+        "public.ContinueWatchingItem": {
+            "type": "object",
+            "properties": {
+                "duration_seconds": {
+                    "type": "integer"
+                },
+                "episode": {
+                    "$ref": "#/definitions/models.NextEpisode"
+                },
+                "movie": {
+                    "$ref": "#/definitions/models.MovieCard"
+                },
+                "position_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "public.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
         "public.ResetPasswordRequest": {
             "type": "object",
             "required": [
@@ -3100,6 +6045,14 @@ const docTemplate = `{
                 }
             }
         },
+        "public.SignOutRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
         "public.SignUpRequest": {
             "type": "object",
             "required": [
@@ -3138,6 +6091,17 @@ const docTemplate = `{
                 }
             }
         },
+        "public.tokenResponse": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "public.updateRequest": {
             "type": "object",
             "properties": {
@@ -3169,6 +6133,23 @@ const docTemplate = `{
                 }
             }
         },
+        "repositories.SearchAllResult": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repositories.SearchResult"
+                    }
+                }
+            }
+        },
         "repositories.SearchResult": {
             "type": "object",
             "properties": {
@@ -6,6 +6,7 @@ import (
 	"ozinshe_production/logger"
 	"ozinshe_production/models"
 	"ozinshe_production/repositories"
+	"ozinshe_production/storage"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -14,10 +15,11 @@ import (
 
 type AgesHandler struct {
 	agesRepo *repositories.AgesRepository
+	storage  storage.Storage
 }
 
-func NewAgesHandler(repo *repositories.AgesRepository) *AgesHandler {
-	return &AgesHandler{agesRepo: repo}
+func NewAgesHandler(repo *repositories.AgesRepository, store storage.Storage) *AgesHandler {
+	return &AgesHandler{agesRepo: repo, storage: store}
 }
 
 type createAgesRequest struct {
@@ -43,6 +45,15 @@ func (h *AgesHandler) FindAll(c *gin.Context) {
 		return
 	}
 
+	for i := range ages {
+		ages[i].PosterUrl, err = storage.ResolveURL(c, h.storage, ages[i].PosterUrl)
+		if err != nil {
+			logger.Error("Failed to build poster URL", zap.Int("id", ages[i].Id), zap.Error(err))
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+	}
+
 	logger.Info("Ages loaded successfully", zap.Int("count", len(ages)))
 	c.JSON(http.StatusOK, ages)
 }
@@ -75,6 +86,13 @@ func (h *AgesHandler) FindById(c *gin.Context) {
 		return
 	}
 
+	ages.PosterUrl, err = storage.ResolveURL(c, h.storage, ages.PosterUrl)
+	if err != nil {
+		logger.Error("Failed to build poster URL", zap.Int("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to build poster URL"))
+		return
+	}
+
 	logger.Info("Ages found", zap.Int("id", id))
 	c.JSON(http.StatusOK, ages)
 }
@@ -87,23 +105,40 @@ func (h *AgesHandler) FindById(c *gin.Context) {
 // @Produce      json
 // @Param        request body createAgesRequest true "Create age request"
 // @Success      200 {object} gin.H "ID of the created age"
-// @Failure      400 {object} models.ApiError "Invalid input"
+// @Failure      400 {object} models.UploadError "Invalid input or poster dimensions"
+// @Failure      413 {object} models.UploadError "Poster larger than 5 MB"
+// @Failure      415 {object} models.UploadError "Poster is not JPEG, PNG or WebP"
 // @Failure      500 {object} models.ApiError "Failed to save poster or create age"
 // @Router       /admin/ages [post]
 func (h *AgesHandler) Create(c *gin.Context) {
 	logger := logger.GetLogger()
 
 	var createAges createAgesRequest
-	err := c.Bind(&createAges)
+	limitUploadBody(c)
+	err := c.ShouldBind(&createAges)
 	if err != nil {
 		logger.Error("Failed to bind request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, models.NewApiError("Couldn't bind json"))
+		respondBindError(c, err, "Couldn't bind json")
 		return
 	}
 
 	logger.Info("Received create ages request", zap.String("title", createAges.Title))
 
-	filename, err := savePoster(c, createAges.Poster)
+	if createAges.Poster == nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Poster is required"))
+		return
+	}
+
+	poster, err := checkImage("poster", models.ImageKindPoster, createAges.Poster)
+	if err != nil {
+		respondUploadError(c, err, "Couldn't read poster")
+		return
+	}
+
+	upload := newStagedUpload(h.storage)
+	defer upload.Rollback(c)
+
+	filename, err := upload.Save(c, poster)
 	if err != nil {
 		logger.Error("Failed to save poster", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
@@ -121,6 +156,7 @@ func (h *AgesHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusNotFound, models.NewApiError(err.Error()))
 		return
 	}
+	upload.Commit()
 
 	logger.Info("Ages created successfully", zap.Int("id", id))
 	c.JSON(http.StatusOK, gin.H{
@@ -197,7 +233,7 @@ func (h *AgesHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	_, err = h.agesRepo.FindById(c, id)
+	ages, err := h.agesRepo.FindById(c, id)
 	if err != nil {
 		logger.Error("Ages not found", zap.Int("id", id), zap.Error(err))
 		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
@@ -211,6 +247,12 @@ func (h *AgesHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	if ages.PosterUrl != "" {
+		if err := h.storage.Delete(c, ages.PosterUrl); err != nil {
+			logger.Warn("Failed to delete ages poster", zap.String("key", ages.PosterUrl), zap.Error(err))
+		}
+	}
+
 	logger.Info("Ages deleted successfully", zap.Int("id", id))
 	c.Status(http.StatusOK)
 }
\ No newline at end of file
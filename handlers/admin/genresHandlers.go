@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"ozinshe_production/models"
 	"ozinshe_production/repositories"
+	"ozinshe_production/storage"
 	"strconv"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -13,10 +14,11 @@ import (
 
 type GenresHandler struct {
 	genresRepo *repositories.GenresRepository
+	storage    storage.Storage
 }
 
-func NewGenresHandler(repo *repositories.GenresRepository) *GenresHandler {
-	return &GenresHandler{genresRepo: repo}
+func NewGenresHandler(repo *repositories.GenresRepository, store storage.Storage) *GenresHandler {
+	return &GenresHandler{genresRepo: repo, storage: store}
 }
 
 type createGenresRequest struct {
@@ -43,6 +45,15 @@ func (h *GenresHandler) FindAll(c *gin.Context) {
 		return
 	}
 
+	for i := range genres {
+		genres[i].PosterUrl, err = storage.ResolveURL(c, h.storage, genres[i].PosterUrl)
+		if err != nil {
+			logger.Error("Failed to build poster URL", zap.Int("id", genres[i].Id), zap.Error(err))
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+	}
+
 	logger.Info("Genres loaded successfully", zap.Int("count", len(genres)))
 	c.JSON(http.StatusOK, genres)
 }
@@ -76,6 +87,13 @@ func (h *GenresHandler) FindById(c *gin.Context) {
 		return
 	}
 
+	genre.PosterUrl, err = storage.ResolveURL(c, h.storage, genre.PosterUrl)
+	if err != nil {
+		logger.Error("Failed to build poster URL", zap.Int("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to build poster URL"))
+		return
+	}
+
 	logger.Info("Genre found", zap.Int("id", id), zap.String("title", genre.Title))
 	c.JSON(http.StatusOK, genre)
 }
@@ -87,23 +105,40 @@ func (h *GenresHandler) FindById(c *gin.Context) {
 // @Accept multipart/form-data
 // @Produce json
 // @Param title formData string true "Title of the genre"
-// @Param poster formData file true "Poster image"
+// @Param poster formData file true "Poster image: JPEG, PNG or WebP up to 5 MB, from 200x200 to 4000x4000"
 // @Success 200 {object} map[string]int "id of the created genre"
-// @Failure 400 {object} models.ApiError
+// @Failure 400 {object} models.UploadError
+// @Failure 413 {object} models.UploadError
+// @Failure 415 {object} models.UploadError
 // @Failure 500 {object} models.ApiError
 // @Router /admin/genres [post]
 func (h *GenresHandler) Create(c *gin.Context) {
 	logger := logger.GetLogger()
 
 	var createGenre createGenresRequest
-	err := c.Bind(&createGenre)
+	limitUploadBody(c)
+	err := c.ShouldBind(&createGenre)
 	if err != nil {
 		logger.Error("Couldn't bind json", zap.Error(err))
-		c.JSON(http.StatusBadRequest, models.NewApiError("Couldn't bind json"))
+		respondBindError(c, err, "Couldn't bind json")
+		return
+	}
+
+	if createGenre.Poster == nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Poster is required"))
+		return
+	}
+
+	poster, err := checkImage("poster", models.ImageKindPoster, createGenre.Poster)
+	if err != nil {
+		respondUploadError(c, err, "Couldn't read poster")
 		return
 	}
 
-	filename, err := savePoster(c, createGenre.Poster)
+	upload := newStagedUpload(h.storage)
+	defer upload.Rollback(c)
+
+	filename, err := upload.Save(c, poster)
 	if err != nil {
 		logger.Error("Failed to save poster", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
@@ -121,6 +156,7 @@ func (h *GenresHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusNotFound, models.NewApiError(err.Error()))
 		return
 	}
+	upload.Commit()
 
 	logger.Info("Genre created successfully", zap.Int("id", id))
 	c.JSON(http.StatusOK, gin.H{
@@ -199,7 +235,7 @@ func (h *GenresHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	_, err = h.genresRepo.FindById(c, id)
+	genre, err := h.genresRepo.FindById(c, id)
 	if err != nil {
 		logger.Error("Failed to find genre", zap.Int("id", id), zap.Error(err))
 		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
@@ -213,6 +249,12 @@ func (h *GenresHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	if genre.PosterUrl != "" {
+		if err := h.storage.Delete(c, genre.PosterUrl); err != nil {
+			logger.Warn("Failed to delete genre poster", zap.String("key", genre.PosterUrl), zap.Error(err))
+		}
+	}
+
 	logger.Info("Genre deleted successfully", zap.Int("id", id))
 	c.Status(http.StatusOK)
 }
@@ -0,0 +1,272 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"ozinshe_production/logger"
+	"ozinshe_production/models"
+	"ozinshe_production/repositories"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type HomepageHandler struct {
+	homepageRepo *repositories.HomepageRepository
+}
+
+func NewHomepageHandler(repo *repositories.HomepageRepository) *HomepageHandler {
+	return &HomepageHandler{homepageRepo: repo}
+}
+
+type homepageRailRequest struct {
+	Type         string     `json:"type"`
+	Title        string     `json:"title"`
+	Size         int        `json:"size"`
+	CategoryId   *int       `json:"category_id"`
+	GenreId      *int       `json:"genre_id"`
+	MovieIds     []int      `json:"movie_ids"`
+	VisibleFrom  *time.Time `json:"visible_from"`
+	VisibleUntil *time.Time `json:"visible_until"`
+}
+
+type reorderRailsRequest struct {
+	RailIds []int `json:"rail_ids" binding:"required"`
+}
+
+func (r homepageRailRequest) toRail() models.HomepageRail {
+	return models.HomepageRail{
+		Type:         r.Type,
+		Title:        r.Title,
+		Size:         r.Size,
+		CategoryId:   r.CategoryId,
+		GenreId:      r.GenreId,
+		MovieIds:     r.MovieIds,
+		VisibleFrom:  r.VisibleFrom,
+		VisibleUntil: r.VisibleUntil,
+	}
+}
+
+// FindAll godoc
+// @Summary      Get homepage layout
+// @Description  Retrieve every homepage rail in display order, including rails outside their visibility window
+// @Tags         homepage
+// @Accept       json
+// @Produce      json
+// @Success      200 {array} models.HomepageRail "Homepage rails"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Router       /admin/homepage [get]
+func (h *HomepageHandler) FindAll(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	rails, err := h.homepageRepo.FindAllRails(c)
+	if err != nil {
+		logger.Error("Failed to load homepage rails", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load homepage rails"))
+		return
+	}
+
+	logger.Info("Homepage rails loaded successfully", zap.Int("count", len(rails)))
+	c.JSON(http.StatusOK, rails)
+}
+
+// FindById godoc
+// @Summary      Get homepage rail by Id
+// @Description  Retrieve a homepage rail by its Id
+// @Tags         homepage
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Rail Id"
+// @Success      200 {object} models.HomepageRail "Homepage rail"
+// @Failure      400 {object} models.ApiError "Invalid rail Id"
+// @Failure      404 {object} models.ApiError "Rail not found"
+// @Router       /admin/homepage/rails/{id} [get]
+func (h *HomepageHandler) FindById(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logger.Error("Invalid rail Id", zap.String("id", c.Param("id")))
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid rail Id"))
+		return
+	}
+
+	rail, err := h.homepageRepo.FindRailById(c, id)
+	if err != nil {
+		logger.Error("Failed to find homepage rail", zap.Int("id", id), zap.Error(err))
+		c.JSON(http.StatusNotFound, models.NewApiError("Rail not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, rail)
+}
+
+// Create godoc
+// @Summary      Create a homepage rail
+// @Description  Add a rail to the end of the homepage. Types: category (category_id), genre (genre_id),
+// @Description  hand_picked (movie_ids in display order), continue_watching, new_releases
+// @Tags         homepage
+// @Accept       json
+// @Produce      json
+// @Param        rail body homepageRailRequest true "Rail settings"
+// @Success      201 {object} map[string]int "Id of the created rail"
+// @Failure      400 {object} models.ApiError "Invalid rail settings"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Router       /admin/homepage/rails [post]
+func (h *HomepageHandler) Create(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	var req homepageRailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request format", zap.Error(err))
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request format"))
+		return
+	}
+
+	rail := req.toRail()
+	if err := rail.Validate(); err != nil {
+		logger.Error("Invalid homepage rail", zap.Error(err))
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
+	}
+
+	id, err := h.homepageRepo.CreateRail(c, rail)
+	if errors.Is(err, repositories.ErrRailReferenceNotFound) {
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to create homepage rail", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to create homepage rail"))
+		return
+	}
+
+	logger.Info("Homepage rail created successfully", zap.Int("id", id), zap.String("type", rail.Type))
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// Update godoc
+// @Summary      Update a homepage rail
+// @Description  Replace the settings of a homepage rail. Its position is changed with PUT /admin/homepage/order
+// @Tags         homepage
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Rail Id"
+// @Param        rail body homepageRailRequest true "Rail settings"
+// @Success      200 "Rail updated"
+// @Failure      400 {object} models.ApiError "Invalid rail settings"
+// @Failure      404 {object} models.ApiError "Rail not found"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Router       /admin/homepage/rails/{id} [put]
+func (h *HomepageHandler) Update(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logger.Error("Invalid rail Id", zap.String("id", c.Param("id")))
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid rail Id"))
+		return
+	}
+
+	var req homepageRailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request format", zap.Error(err))
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request format"))
+		return
+	}
+
+	rail := req.toRail()
+	if err := rail.Validate(); err != nil {
+		logger.Error("Invalid homepage rail", zap.Error(err))
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
+	}
+
+	err = h.homepageRepo.UpdateRail(c, id, rail)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, models.NewApiError("Rail not found"))
+		return
+	}
+	if errors.Is(err, repositories.ErrRailReferenceNotFound) {
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to update homepage rail", zap.Int("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to update homepage rail"))
+		return
+	}
+
+	logger.Info("Homepage rail updated successfully", zap.Int("id", id))
+	c.Status(http.StatusOK)
+}
+
+// Delete godoc
+// @Summary      Delete a homepage rail
+// @Description  Remove a rail from the homepage
+// @Tags         homepage
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Rail Id"
+// @Success      200 {object} map[string]string "Rail deleted successfully"
+// @Failure      400 {object} models.ApiError "Invalid rail Id"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Router       /admin/homepage/rails/{id} [delete]
+func (h *HomepageHandler) Delete(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logger.Error("Invalid rail Id", zap.String("id", c.Param("id")))
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid rail Id"))
+		return
+	}
+
+	if err := h.homepageRepo.DeleteRail(c, id); err != nil {
+		logger.Error("Failed to delete homepage rail", zap.Int("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to delete homepage rail"))
+		return
+	}
+
+	logger.Info("Homepage rail deleted successfully", zap.Int("id", id))
+	c.JSON(http.StatusOK, gin.H{"message": "Rail deleted successfully"})
+}
+
+// Reorder godoc
+// @Summary      Reorder homepage rails
+// @Description  Set the display order of the homepage. rail_ids must list every rail exactly once
+// @Tags         homepage
+// @Accept       json
+// @Produce      json
+// @Param        order body reorderRailsRequest true "Rail Ids in display order"
+// @Success      200 "Order saved"
+// @Failure      400 {object} models.ApiError "Invalid order"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Router       /admin/homepage/order [put]
+func (h *HomepageHandler) Reorder(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	var req reorderRailsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request format", zap.Error(err))
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request format"))
+		return
+	}
+
+	err := h.homepageRepo.ReorderRails(c, req.RailIds)
+	if errors.Is(err, repositories.ErrRailsOrderMismatch) {
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to reorder homepage rails", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to reorder homepage rails"))
+		return
+	}
+
+	logger.Info("Homepage rails reordered", zap.Ints("rail_ids", req.RailIds))
+	c.Status(http.StatusOK)
+}
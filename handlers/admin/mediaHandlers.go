@@ -1,12 +1,17 @@
 package admin
 
 import (
+	"errors"
+	"fmt"
 	"mime/multipart"
 	"net/http"
+	"ozinshe_production/config"
 	"ozinshe_production/logger"
 	"ozinshe_production/models"
 	"ozinshe_production/repositories"
+	"ozinshe_production/storage"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -14,10 +19,11 @@ import (
 
 type MediaHandler struct {
 	mediaRepo *repositories.MediaRepository
+	storage   storage.Storage
 }
 
-func NewMediaHandler(repo *repositories.MediaRepository) *MediaHandler {
-	return &MediaHandler{mediaRepo: repo}
+func NewMediaHandler(repo *repositories.MediaRepository, store storage.Storage) *MediaHandler {
+	return &MediaHandler{mediaRepo: repo, storage: store}
 }
 
 
@@ -38,7 +44,20 @@ func (h *MediaHandler) GetMovieMedia(c *gin.Context) {
     }
 
     media, err := h.mediaRepo.GetMovieMedia(c, movieId)
+    if errors.Is(err, repositories.ErrMovieNotFound) {
+        c.JSON(http.StatusNotFound, models.NewApiError("Movie not found"))
+        return
+    }
     if err != nil {
+        logger.Error("Failed to get movie media", zap.Int("movieId", movieId), zap.Error(err))
+        c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't get movie media"))
+        return
+    }
+
+    // В базе лежат ключи хранилища, клиенту отдаём ссылки
+    err = storage.ResolveMovieMedia(c, h.storage, media)
+    if err != nil {
+        logger.Error("Failed to build media URLs", zap.Int("movieId", movieId), zap.Error(err))
         c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't get movie media"))
         return
     }
@@ -51,9 +70,10 @@ func (h *MediaHandler) UploadMovieMedia(c *gin.Context) {
     logger := logger.GetLogger()
 
     // Привязка данных формы к структуре request
+    limitUploadBody(c)
     if err := c.ShouldBind(&request); err != nil {
         logger.Error("Failed to bind multipart form", zap.Error(err))
-        c.JSON(http.StatusBadRequest, models.NewApiError("Failed to bind form data"))
+        respondBindError(c, err, "Failed to bind form data")
         return
     }
 
@@ -65,13 +85,44 @@ func (h *MediaHandler) UploadMovieMedia(c *gin.Context) {
         return
     }
 
+    if len(request.Screenshots) > maxScreenshotsPerUpload {
+        c.JSON(http.StatusBadRequest, models.NewApiError(fmt.Sprintf("At most %d screenshots per upload", maxScreenshotsPerUpload)))
+        return
+    }
+
+    // Все файлы проверяются до сохранения, чтобы отклонённый скриншот не оставил загруженную обложку
+    var cover *checkedImage
+    if request.Cover != nil {
+        image, err := checkImage("cover", models.ImageKindCover, request.Cover)
+        if err != nil {
+            respondUploadError(c, err, "Couldn't read cover")
+            return
+        }
+        cover = &image
+    }
+
+    screenshots := make([]checkedImage, 0, len(request.Screenshots))
+    for _, file := range request.Screenshots {
+        image, err := checkImage("screenshots", models.ImageKindScreenshot, file)
+        if err != nil {
+            respondUploadError(c, err, "Couldn't read screenshot")
+            return
+        }
+        screenshots = append(screenshots, image)
+    }
+
     var coverFilename *string
     var screenshotFilenames []string
 
+    // Если сохранение одного из файлов или запись в базу не удастся, уже сохранённые файлы удаляются
+    upload := newStagedUpload(h.storage)
+    defer upload.Rollback(c)
+
     // Обработка обложки
-    if request.Cover != nil {
-        filename, err := savePoster(c, request.Cover)
+    if cover != nil {
+        filename, err := upload.Save(c, *cover)
         if err != nil {
+            logger.Error("Failed to save cover", zap.Error(err))
             c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't save cover"))
             return
         }
@@ -79,9 +130,10 @@ func (h *MediaHandler) UploadMovieMedia(c *gin.Context) {
     }
 
     // Обработка скриншотов
-    for _, file := range request.Screenshots {
-        filename, err := savePoster(c, file)
+    for _, image := range screenshots {
+        filename, err := upload.Save(c, image)
         if err != nil {
+            logger.Error("Failed to save screenshot", zap.Error(err))
             c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't save screenshot"))
             return
         }
@@ -90,10 +142,16 @@ func (h *MediaHandler) UploadMovieMedia(c *gin.Context) {
 
     // Обновление в БД
     err = h.mediaRepo.UpdateMovieMedia(c, movieId, coverFilename, screenshotFilenames)
+    if errors.Is(err, repositories.ErrMovieNotFound) {
+        c.JSON(http.StatusNotFound, models.NewApiError("Movie not found"))
+        return
+    }
     if err != nil {
+        logger.Error("Failed to update movie media", zap.Int("movieId", movieId), zap.Error(err))
         c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't update movie media"))
         return
     }
+    upload.Commit()
 
     c.JSON(http.StatusOK, gin.H{"message": "Media uploaded successfully"})
 }
@@ -110,32 +168,61 @@ func (h *MediaHandler) UploadSingleMovieMedia(c *gin.Context) {
         return
     }
 
+    // Форма разбирается под ограничением размера тела, поэтому файл читается раньше типа
+    limitUploadBody(c)
+    file, err := c.FormFile("file")
+    if err != nil {
+        respondBindError(c, err, "No file uploaded")
+        return
+    }
+
     mediaType := c.DefaultPostForm("type", "")
-    if mediaType != "cover" && mediaType != "screenshot" {
+    if mediaType != models.ImageKindCover && mediaType != models.ImageKindScreenshot {
         logger.Error("Invalid media type", zap.String("mediaType", mediaType))
         c.JSON(http.StatusBadRequest, models.NewApiError("Invalid media type. Expected 'cover' or 'screenshot'"))
         return
     }
 
-    file, err := c.FormFile("file")
+    image, err := checkImage("file", mediaType, file)
     if err != nil {
-        c.JSON(http.StatusBadRequest, models.NewApiError("No file uploaded"))
+        respondUploadError(c, err, "Couldn't read media file")
         return
     }
 
-    filename, err := savePoster(c, file)
+    upload := newStagedUpload(h.storage)
+    defer upload.Rollback(c)
+
+    filename, err := upload.Save(c, image)
     if err != nil {
+        logger.Error("Failed to save media file", zap.Error(err))
         c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't save media file"))
         return
     }
 
-    err = h.mediaRepo.UpdateSingleMovieMedia(c, movieId, mediaType, filename)
+    mediaId, err := h.mediaRepo.UpdateSingleMovieMedia(c, movieId, mediaType, filename)
+    if errors.Is(err, repositories.ErrMovieNotFound) {
+        c.JSON(http.StatusNotFound, models.NewApiError("Movie not found"))
+        return
+    }
     if err != nil {
+        logger.Error("Failed to update movie media", zap.Int("movieId", movieId), zap.Error(err))
         c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't update movie media"))
         return
     }
+    upload.Commit()
 
-    c.JSON(http.StatusOK, gin.H{"message": "Media uploaded successfully"})
+    url, err := h.storage.URL(c, filename)
+    if err != nil {
+        logger.Error("Failed to build media URL", zap.String("key", filename), zap.Error(err))
+        c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't build media URL"))
+        return
+    }
+
+    response := gin.H{"message": "Media uploaded successfully", "url": url}
+    if mediaType == models.ImageKindScreenshot {
+        response["id"] = mediaId
+    }
+    c.JSON(http.StatusOK, response)
 }
 
 
@@ -158,12 +245,239 @@ func (h *MediaHandler) DeleteMovieMedia(c *gin.Context) {
         return
     }
 
-    err = h.mediaRepo.DeleteMovieMedia(c, movieId, req.ImageURL)
+    // Клиент присылает выданную ему ссылку или сам ключ
+    key := storage.KeyFromURL(req.ImageURL)
+    removed, err := h.mediaRepo.DeleteMovieMedia(c, movieId, key)
     if err != nil {
         c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't delete movie media"))
         return
     }
 
+    if removed {
+        if err := h.storage.Delete(c, key); err != nil {
+            logger.Warn("Failed to delete media file", zap.String("key", key), zap.Error(err))
+        }
+    }
+
     c.JSON(http.StatusOK, gin.H{"message": "Media deleted successfully"})
 }
 
+type reorderMediaRequest struct {
+    Ids []int `json:"ids" binding:"required"`
+}
+
+// ReorderMovieMedia godoc
+// @Summary      Reorder movie screenshots
+// @Description  Sets the gallery order of the movie. ids must list every screenshot of the movie exactly once
+// @Tags         media
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Movie ID"
+// @Param        order body reorderMediaRequest true "Screenshot ids in display order"
+// @Success      200 {object} models.MovieMedia "Cover and gallery in the new order"
+// @Failure      400 {object} models.ApiError "Invalid order"
+// @Failure      404 {object} models.ApiError "Movie not found"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Security     Bearer
+// @Router       /admin/media/movies/{id}/order [put]
+func (h *MediaHandler) ReorderMovieMedia(c *gin.Context) {
+    logger := logger.GetLogger()
+
+    movieId, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, models.NewApiError("Invalid movie ID"))
+        return
+    }
+
+    var req reorderMediaRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request format"))
+        return
+    }
+
+    err = h.mediaRepo.ReorderMovieMedia(c, movieId, req.Ids)
+    switch {
+    case errors.Is(err, repositories.ErrMovieNotFound):
+        c.JSON(http.StatusNotFound, models.NewApiError("Movie not found"))
+        return
+    case errors.Is(err, repositories.ErrMediaOrderMismatch):
+        c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+        return
+    case err != nil:
+        logger.Error("Failed to reorder movie media", zap.Int("movieId", movieId), zap.Error(err))
+        c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't reorder movie media"))
+        return
+    }
+
+    logger.Info("Movie media reordered", zap.Int("movieId", movieId), zap.Ints("ids", req.Ids))
+    h.GetMovieMedia(c)
+}
+
+// UpdateMovieMediaItem godoc
+// @Summary      Update a screenshot
+// @Description  Changes the caption, alt text or hero flag of a screenshot. Marking a screenshot as hero
+// @Description  clears the flag on the others
+// @Tags         media
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Movie ID"
+// @Param        mediaId path int true "Screenshot ID"
+// @Param        patch body models.MovieMediaPatch true "Fields to change"
+// @Success      200 {object} models.MovieMediaItem "Updated screenshot"
+// @Failure      400 {object} models.ApiError "Invalid data"
+// @Failure      404 {object} models.ApiError "Movie or screenshot not found"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Security     Bearer
+// @Router       /admin/media/movies/{id}/items/{mediaId} [patch]
+func (h *MediaHandler) UpdateMovieMediaItem(c *gin.Context) {
+    logger := logger.GetLogger()
+
+    movieId, mediaId, ok := parseMediaItemIds(c)
+    if !ok {
+        return
+    }
+
+    var patch models.MovieMediaPatch
+    if err := c.ShouldBindJSON(&patch); err != nil {
+        c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request format"))
+        return
+    }
+    if err := patch.Validate(); err != nil {
+        c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+        return
+    }
+
+    item, err := h.mediaRepo.UpdateMovieMediaItem(c, movieId, mediaId, patch)
+    switch {
+    case errors.Is(err, repositories.ErrMovieNotFound):
+        c.JSON(http.StatusNotFound, models.NewApiError("Movie not found"))
+        return
+    case errors.Is(err, repositories.ErrMediaNotFound):
+        c.JSON(http.StatusNotFound, models.NewApiError("Screenshot not found"))
+        return
+    case err != nil:
+        logger.Error("Failed to update movie media", zap.Int("movieId", movieId), zap.Int("mediaId", mediaId), zap.Error(err))
+        c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't update screenshot"))
+        return
+    }
+
+    item.URL, err = storage.ResolveURL(c, h.storage, item.Key)
+    if err != nil {
+        logger.Error("Failed to build media URL", zap.String("key", item.Key), zap.Error(err))
+        c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't build media URL"))
+        return
+    }
+
+    c.JSON(http.StatusOK, item)
+}
+
+// DeleteMovieMediaItem godoc
+// @Summary      Delete a screenshot
+// @Description  Removes a screenshot from the gallery and deletes its file
+// @Tags         media
+// @Produce      json
+// @Param        id path int true "Movie ID"
+// @Param        mediaId path int true "Screenshot ID"
+// @Success      200 "Screenshot deleted"
+// @Failure      400 {object} models.ApiError "Invalid ID"
+// @Failure      404 {object} models.ApiError "Screenshot not found"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Security     Bearer
+// @Router       /admin/media/movies/{id}/items/{mediaId} [delete]
+func (h *MediaHandler) DeleteMovieMediaItem(c *gin.Context) {
+    logger := logger.GetLogger()
+
+    movieId, mediaId, ok := parseMediaItemIds(c)
+    if !ok {
+        return
+    }
+
+    key, err := h.mediaRepo.DeleteMovieMediaItem(c, movieId, mediaId)
+    if errors.Is(err, repositories.ErrMediaNotFound) {
+        c.JSON(http.StatusNotFound, models.NewApiError("Screenshot not found"))
+        return
+    }
+    if err != nil {
+        logger.Error("Failed to delete movie media", zap.Int("movieId", movieId), zap.Int("mediaId", mediaId), zap.Error(err))
+        c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't delete screenshot"))
+        return
+    }
+
+    if err := h.storage.Delete(c, key); err != nil {
+        logger.Warn("Failed to delete media file", zap.String("key", key), zap.Error(err))
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Media deleted successfully"})
+}
+
+// CollectOrphanedMedia godoc
+// @Summary      Collect orphaned media
+// @Description  Finds files in the media storage that no movie, genre or age rating references and that are older
+// @Description  than the grace period. By default only reports them; pass dry_run=false to delete
+// @Tags         media
+// @Produce      json
+// @Param        dry_run      query bool   false "Only report orphaned files (default true)"
+// @Param        grace_period query string false "Minimum age of a file to delete, e.g. 48h (default MEDIA_GC_GRACE_PERIOD, at least 10m)"
+// @Success      200 {object} models.MediaGCReport "Orphaned files and what was deleted"
+// @Failure      400 {object} models.ApiError "Invalid dry_run or grace_period"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Security     Bearer
+// @Router       /admin/media/gc [post]
+func (h *MediaHandler) CollectOrphanedMedia(c *gin.Context) {
+    logger := logger.GetLogger()
+
+    dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "true"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, models.NewApiError("Invalid dry_run"))
+        return
+    }
+
+    grace := config.Config.MediaGCGracePeriod
+    if value := c.Query("grace_period"); value != "" {
+        grace, err = time.ParseDuration(value)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, models.NewApiError("Invalid grace_period"))
+            return
+        }
+    }
+    if grace < storage.MinGCGracePeriod {
+        c.JSON(http.StatusBadRequest, models.NewApiError(fmt.Sprintf("grace_period must be at least %s", storage.MinGCGracePeriod)))
+        return
+    }
+
+    // Ссылки читаются до обхода хранилища: файлы, загруженные позже, моложе grace_period
+    referenced, err := h.mediaRepo.ReferencedMediaKeys(c)
+    if err != nil {
+        logger.Error("Failed to load referenced media", zap.Error(err))
+        c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't collect orphaned media"))
+        return
+    }
+
+    report, err := storage.CollectGarbage(c, h.storage, referenced, grace, dryRun)
+    if err != nil {
+        logger.Error("Failed to collect orphaned media", zap.Error(err))
+        c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't collect orphaned media"))
+        return
+    }
+
+    logger.Info("Orphaned media collected",
+        zap.Bool("dryRun", dryRun),
+        zap.Int("orphans", len(report.Orphans)),
+        zap.Int("deleted", report.Deleted),
+        zap.Int("failed", report.Failed))
+    c.JSON(http.StatusOK, report)
+}
+
+func parseMediaItemIds(c *gin.Context) (int, int, bool) {
+    movieId, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, models.NewApiError("Invalid movie ID"))
+        return 0, 0, false
+    }
+    mediaId, err := strconv.Atoi(c.Param("mediaId"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, models.NewApiError("Invalid media ID"))
+        return 0, 0, false
+    }
+    return movieId, mediaId, true
+}
@@ -1,11 +1,16 @@
 package admin
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"ozinshe_production/logger"
 	"ozinshe_production/models"
 	"ozinshe_production/repositories"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -83,24 +88,56 @@ func (h *MoviesHandler) FindById(c *gin.Context) {
 }
 
 // @Summary Get all movies
-// @Description Get a list of all movies
+// @Description Get a page of movies matching the filters, sorted by title, release_year or created_at, with the total count
 // @Tags Movies
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Movie
+// @Param genreids query string false "Comma-separated genre IDs"
+// @Param genrematch query string false "any (default) or all"
+// @Param categoryids query string false "Comma-separated category IDs"
+// @Param categorymatch query string false "any (default) or all"
+// @Param typeids query string false "Comma-separated movie type IDs"
+// @Param ageids query string false "Comma-separated age IDs"
+// @Param agematch query string false "any (default) or all"
+// @Param yearfrom query int false "Minimum release year"
+// @Param yearto query int false "Maximum release year"
+// @Param runtimefrom query int false "Minimum runtime in minutes"
+// @Param runtimeto query int false "Maximum runtime in minutes"
+// @Param director query string false "Director name substring"
+// @Param producer query string false "Producer name substring"
+// @Param keywords query string false "Comma-separated keywords"
+// @Param keywordmatch query string false "any (default) or all"
+// @Param status query string false "Comma-separated statuses: draft, scheduled, published, archived (default all)"
+// @Param sort query string false "Sort key: title, release_year, created_at (default)"
+// @Param order query string false "Sort order: asc or desc"
+// @Param limit query int false "Page size, 1-100 (default 20)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Success 200 {object} models.MoviesPage
+// @Failure 400 {object} models.ApiError
 // @Failure 404 {object} models.ApiError
 // @Router /admin/movies [get]
 func (h *MoviesHandler) FindAll(c *gin.Context) {
 	logger := logger.GetLogger()
 
-	filters := models.Moviesfilters {
-		GenreIds: 		c.Query("genreids"),
-		CategoryIds: 	c.Query("categoryids"),
-		TypeIds: 		c.Query("typeids"),
-		AgeIds: 		c.Query("ageids"),
+	filters, err := parseMoviesFilters(c)
+	if err != nil {
+		logger.Error("Invalid movies filters", zap.Error(err))
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
 	}
 
-	movies, err := h.moviesRepo.FindAll(c, filters)
+	if err := filters.Validate(); err != nil {
+		logger.Error("Invalid movies filters", zap.Error(err))
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
+	}
+
+	page, err := h.moviesRepo.FindAll(c, filters)
+	if errors.Is(err, repositories.ErrInvalidCursor) {
+		logger.Error("Invalid movies cursor", zap.String("cursor", filters.Cursor))
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
+	}
 	if err != nil {
 		// Логируем ошибку и возвращаем ответ с ошибкой
 		logger.Error("Failed to load movies", zap.Error(err))
@@ -109,8 +146,8 @@ func (h *MoviesHandler) FindAll(c *gin.Context) {
 	}
 
 	// Если фильмы найдены, возвращаем их в ответе
-	logger.Info("Movies loaded successfully", zap.Int("count", len(movies)))
-	c.JSON(http.StatusOK, movies)
+	logger.Info("Movies loaded successfully", zap.Int("count", len(page.Movies)), zap.Int("total", page.Total))
+	c.JSON(http.StatusOK, page)
 }
 
 // @Summary Create a new movie
@@ -328,3 +365,159 @@ func (h *MoviesHandler) Delete(c *gin.Context) {
     c.JSON(http.StatusOK, gin.H{"message": "Movie deleted successfully"})
 }
 
+
+type changeMovieStatusRequest struct {
+	Status      string     `json:"status" binding:"required"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// @Summary Change movie status
+// @Description Move a movie between draft, scheduled, published and archived.
+// @Description scheduled requires publish_at in the future; published and scheduled accept an optional unpublish_at.
+// @Description Only published movies are visible in public endpoints
+// @Tags Movies
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param status body changeMovieStatusRequest true "New status and schedule"
+// @Success 200 {object} models.Movie
+// @Failure 400 {object} models.ApiError
+// @Failure 404 {object} models.ApiError
+// @Failure 500 {object} models.ApiError
+// @Router /admin/movies/{id}/status [put]
+func (h *MoviesHandler) ChangeStatus(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	idStr := c.Param("id")
+	movieId, err := strconv.Atoi(idStr)
+	if err != nil {
+		logger.Error("Invalid movie ID", zap.String("id", idStr), zap.Error(err))
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid movie ID"))
+		return
+	}
+
+	var request changeMovieStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Failed to bind request payload", zap.Error(err))
+		c.JSON(http.StatusBadRequest, models.NewApiError("Couldn't bind payload"))
+		return
+	}
+
+	movie, err := h.moviesRepo.FindById(c, movieId)
+	if err != nil || movie.Id == 0 {
+		logger.Error("Movie not found", zap.Int("id", movieId), zap.Error(err))
+		c.JSON(http.StatusNotFound, models.NewApiError("Movie not found"))
+		return
+	}
+
+	change := models.MovieStatusChange{
+		Status:      request.Status,
+		PublishAt:   request.PublishAt,
+		UnpublishAt: request.UnpublishAt,
+	}
+	if err := change.Validate(movie, time.Now()); err != nil {
+		logger.Error("Invalid movie status change", zap.Int("id", movieId), zap.String("from", movie.Status), zap.Error(err))
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
+	}
+
+	err = h.moviesRepo.UpdateStatus(c, movieId, change)
+	if err != nil {
+		logger.Error("Failed to change movie status", zap.Int("id", movieId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to change movie status"))
+		return
+	}
+
+	movie.Status, movie.PublishAt, movie.UnpublishAt = change.Status, change.PublishAt, change.UnpublishAt
+
+	logger.Info("Movie status changed", zap.Int("id", movieId), zap.String("status", change.Status))
+	c.JSON(http.StatusOK, movie)
+}
+
+// parseMoviesFilters разбирает query-параметры списка фильмов. Значения проверяются в Moviesfilters.Validate
+func parseMoviesFilters(c *gin.Context) (models.Moviesfilters, error) {
+	filters := models.Moviesfilters{
+		GenreMatch:    c.Query("genrematch"),
+		CategoryMatch: c.Query("categorymatch"),
+		AgeMatch:      c.Query("agematch"),
+		Director:      strings.TrimSpace(c.Query("director")),
+		Producer:      strings.TrimSpace(c.Query("producer")),
+		KeywordMatch:  c.Query("keywordmatch"),
+		Sort:          c.Query("sort"),
+		Order:         c.Query("order"),
+		Cursor:        c.Query("cursor"),
+	}
+
+	lists := []struct {
+		name   string
+		target *[]int
+	}{
+		{"genreids", &filters.GenreIds},
+		{"categoryids", &filters.CategoryIds},
+		{"typeids", &filters.TypeIds},
+		{"ageids", &filters.AgeIds},
+	}
+	for _, list := range lists {
+		ids, err := parseIdList(c.Query(list.name))
+		if err != nil {
+			return models.Moviesfilters{}, fmt.Errorf("%s must be a comma-separated list of positive integers", list.name)
+		}
+		*list.target = ids
+	}
+
+	numbers := []struct {
+		name   string
+		target *int
+	}{
+		{"yearfrom", &filters.YearFrom},
+		{"yearto", &filters.YearTo},
+		{"runtimefrom", &filters.RuntimeFrom},
+		{"runtimeto", &filters.RuntimeTo},
+		{"limit", &filters.Limit},
+	}
+	for _, number := range numbers {
+		value := c.Query(number.name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return models.Moviesfilters{}, fmt.Errorf("%s must be an integer", number.name)
+		}
+		*number.target = n
+	}
+
+	for _, status := range strings.Split(c.Query("status"), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			filters.Statuses = append(filters.Statuses, status)
+		}
+	}
+
+	for _, keyword := range strings.Split(c.Query("keywords"), ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			filters.Keywords = append(filters.Keywords, keyword)
+		}
+	}
+
+	return filters, nil
+}
+
+// parseIdList разбирает "1,2,3" в список без повторов. Пустая строка — пустой список
+func parseIdList(value string) ([]int, error) {
+	var ids []int
+	if value == "" {
+		return ids, nil
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid id %q", part)
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -1,18 +1,180 @@
 package admin
 
 import (
-	"path/filepath"
-	"github.com/google/uuid"
+	"context"
+	"errors"
 	"fmt"
 	"mime/multipart"
+	"net/http"
+	"ozinshe_production/imaging"
+	"ozinshe_production/logger"
+	"ozinshe_production/models"
+	"ozinshe_production/storage"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
+// maxScreenshotsPerUpload — сколько скриншотов можно загрузить одним запросом
+const maxScreenshotsPerUpload = 20
+
+// maxUploadRequestSize — предел тела запроса с файлами: обложка, скриншоты и поля формы
+var maxUploadRequestSize = models.ImageRules[models.ImageKindCover].MaxBytes +
+	maxScreenshotsPerUpload*models.ImageRules[models.ImageKindScreenshot].MaxBytes + 1<<20
+
+// uploadError — файл не прошёл проверку; клиент получает 4xx с телом models.UploadError
+type uploadError struct {
+	status int
+	body   models.UploadError
+}
+
+func (e *uploadError) Error() string {
+	return e.body.ApiError.Error
+}
+
+func rejectUpload(status int, code, field string, file *multipart.FileHeader, rule models.ImageRule, msg string) *uploadError {
+	return &uploadError{
+		status: status,
+		body: models.UploadError{
+			ApiError: models.NewApiError(msg),
+			Code:     code,
+			Field:    field,
+			Filename: file.Filename,
+			Rule:     &rule,
+		},
+	}
+}
+
+// checkedImage — загруженный файл, прошедший проверку, и его формат по содержимому
+type checkedImage struct {
+	file *multipart.FileHeader
+	info imaging.Info
+}
+
+// checkImage проверяет файл из поля формы field по правилам вида kind: размер, формат по сигнатуре и размеры
+func checkImage(field, kind string, file *multipart.FileHeader) (checkedImage, error) {
+	rule := models.ImageRules[kind]
+	if file.Size > rule.MaxBytes {
+		return checkedImage{}, rejectUpload(http.StatusRequestEntityTooLarge, models.UploadErrFileTooLarge, field, file, rule,
+			fmt.Sprintf("%s must not exceed %d MB", file.Filename, rule.MaxBytes>>20))
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return checkedImage{}, err
+	}
+	defer f.Close()
+
+	info, err := imaging.Inspect(f)
+	switch {
+	case errors.Is(err, imaging.ErrUnsupportedFormat):
+		return checkedImage{}, rejectUpload(http.StatusUnsupportedMediaType, models.UploadErrUnsupportedType, field, file, rule,
+			fmt.Sprintf("%s: %v", file.Filename, err))
+	case errors.Is(err, imaging.ErrInvalidImage):
+		return checkedImage{}, rejectUpload(http.StatusBadRequest, models.UploadErrInvalidImage, field, file, rule,
+			fmt.Sprintf("%s: %v", file.Filename, err))
+	case err != nil:
+		return checkedImage{}, err
+	}
+
+	if !rule.FitsDimensions(kind, info.Width, info.Height) {
+		msg := fmt.Sprintf("%s is %dx%d, %s must be from %dx%d to %dx%d", file.Filename, info.Width, info.Height,
+			kind, rule.MinWidth, rule.MinHeight, rule.MaxWidth, rule.MaxHeight)
+		if kind == models.ImageKindCover {
+			msg += " and not wider than tall"
+		}
+		return checkedImage{}, rejectUpload(http.StatusBadRequest, models.UploadErrInvalidDimensions, field, file, rule, msg)
+	}
+
+	return checkedImage{file: file, info: info}, nil
+}
+
+// saveImage сохраняет проверенный файл под новым ключом и возвращает ключ. Расширение и Content-Type
+// берутся из формата файла, а не из того, что прислал клиент
+func saveImage(c *gin.Context, store storage.Storage, image checkedImage) (string, error) {
+	key := uuid.NewString() + image.info.Ext()
+
+	file, err := image.file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	err = store.Put(c, key, file, image.file.Size, image.info.ContentType())
+	return key, err
+}
+
+// stagedUpload собирает файлы, сохранённые за один запрос, пока запись о них не попала в базу.
+// Работает как транзакция: defer Rollback сразу после создания, Commit после успешной записи в базу.
+// Если процесс упадёт между сохранением и записью, оставшиеся файлы удалит сборка мусора
+type stagedUpload struct {
+	storage   storage.Storage
+	keys      []string
+	committed bool
+}
+
+func newStagedUpload(store storage.Storage) *stagedUpload {
+	return &stagedUpload{storage: store}
+}
+
+// Save сохраняет файл через saveImage и запоминает его ключ
+func (u *stagedUpload) Save(c *gin.Context, image checkedImage) (string, error) {
+	key, err := saveImage(c, u.storage, image)
+	if err != nil {
+		return "", err
+	}
+	u.keys = append(u.keys, key)
+	return key, nil
+}
+
+// Commit отмечает, что база ссылается на сохранённые файлы и удалять их нельзя
+func (u *stagedUpload) Commit() {
+	u.committed = true
+}
+
+// Rollback удаляет сохранённые файлы, если не было Commit. Запрос к этому моменту мог быть отменён
+// клиентом, поэтому удаление идёт без его отмены
+func (u *stagedUpload) Rollback(c *gin.Context) {
+	if u.committed {
+		return
+	}
+
+	ctx := context.WithoutCancel(c.Request.Context())
+	for _, key := range u.keys {
+		if err := u.storage.Delete(ctx, key); err != nil {
+			logger.GetLogger().Warn("Failed to delete staged media file", zap.String("key", key), zap.Error(err))
+		}
+	}
+	u.keys = nil
+}
+
+// limitUploadBody обрывает чтение тела после maxUploadRequestSize, чтобы огромный файл не сохранялся
+// во временные файлы до проверки
+func limitUploadBody(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadRequestSize)
+}
 
-func savePoster(c *gin.Context, poster *multipart.FileHeader) (string, error) {
-	filename := fmt.Sprintf("%s%s", uuid.NewString(), filepath.Ext(poster.Filename))
-	filepath := fmt.Sprintf("images/%s", filename)
-	err := c.SaveUploadedFile(poster, filepath)
+// respondBindError отвечает 413 на слишком большое тело запроса и 400 с message на прочие ошибки разбора формы
+func respondBindError(c *gin.Context, err error, message string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, models.UploadError{
+			ApiError: models.NewApiError(fmt.Sprintf("request must not exceed %d MB", tooLarge.Limit>>20)),
+			Code:     models.UploadErrFileTooLarge,
+		})
+		return
+	}
+	c.JSON(http.StatusBadRequest, models.NewApiError(message))
+}
 
-	return filename, err
-}
\ No newline at end of file
+// respondUploadError отвечает 4xx на отклонённый файл и 500 с message на прочие ошибки
+func respondUploadError(c *gin.Context, err error, message string) {
+	var rejected *uploadError
+	if errors.As(err, &rejected) {
+		c.JSON(rejected.status, rejected.body)
+		return
+	}
+	logger.GetLogger().Error(message, zap.Error(err))
+	c.JSON(http.StatusInternalServerError, models.NewApiError(message))
+}
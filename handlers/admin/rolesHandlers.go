@@ -20,13 +20,8 @@ func NewRolesHandler(repo *repositories.RolesRepository) *RolesHandler {
 }
 
 type createRoleRequest struct {
-	Name              string `json:"name" binding:"required"`
-	CanEditProjects   bool   `json:"can_edit_projects"`
-	CanEditCategories bool   `json:"can_edit_categories"`
-	CanEditUsers      bool   `json:"can_edit_users"`
-	CanEditRoles      bool   `json:"can_edit_roles"`
-	CanEditGenres     bool   `json:"can_edit_genres"`
-	CanEditAges       bool   `json:"can_edit_ages"`
+	Name        string   `json:"name" binding:"required"`
+	Permissions []string `json:"permissions"`
 }
 
 // @Summary Get all roles
@@ -83,7 +78,7 @@ func (h *RolesHandler) FindById(c *gin.Context) {
 }
 
 // @Summary Create a new role
-// @Description Create a new role with specified permissions
+// @Description Create a new role with the specified set of permissions, e.g. "movies:write"
 // @Tags Roles
 // @Accept json
 // @Produce json
@@ -103,14 +98,19 @@ func (h *RolesHandler) Create(c *gin.Context) {
 		return
 	}
 
+	if unknown, err := h.findUnknownPermission(c, createRole.Permissions); err != nil {
+		logger.Error("Failed to load permissions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		return
+	} else if unknown != "" {
+		logger.Warn("Unknown permission in role request", zap.String("permission", unknown))
+		c.JSON(http.StatusBadRequest, models.NewApiError("Unknown permission: "+unknown))
+		return
+	}
+
 	role := models.Role{
-		Name:              createRole.Name,
-		CanEditProjects:   createRole.CanEditProjects,
-		CanEditCategories: createRole.CanEditCategories,
-		CanEditUsers:      createRole.CanEditUsers,
-		CanEditRoles:      createRole.CanEditRoles,
-		CanEditGenres:     createRole.CanEditGenres,
-		CanEditAges:       createRole.CanEditAges,
+		Name:        createRole.Name,
+		Permissions: createRole.Permissions,
 	}
 
 	id, err := h.rolesRepo.Create(c, role)
@@ -125,12 +125,12 @@ func (h *RolesHandler) Create(c *gin.Context) {
 }
 
 // @Summary Update an existing role
-// @Description Update an existing role with new information
+// @Description Update an existing role's name and replace its set of permissions
 // @Tags Roles
 // @Accept json
 // @Produce json
 // @Param id path int true "Role ID"
-// @Param role body models.Role true "Updated role data"
+// @Param role body createRoleRequest true "Updated role data"
 // @Success 200 {string} string "OK"
 // @Failure 400 {object} models.ApiError
 // @Failure 404 {object} models.ApiError
@@ -154,7 +154,7 @@ func (h *RolesHandler) Update(c *gin.Context) {
 		return
 	}
 
-	var updateRole models.Role
+	var updateRole createRoleRequest
 	err = c.BindJSON(&updateRole)
 	if err != nil {
 		logger.Error("Couldn't bind JSON", zap.Error(err))
@@ -162,7 +162,20 @@ func (h *RolesHandler) Update(c *gin.Context) {
 		return
 	}
 
-	err = h.rolesRepo.Update(c, id, updateRole)
+	if unknown, err := h.findUnknownPermission(c, updateRole.Permissions); err != nil {
+		logger.Error("Failed to load permissions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		return
+	} else if unknown != "" {
+		logger.Warn("Unknown permission in role request", zap.String("permission", unknown))
+		c.JSON(http.StatusBadRequest, models.NewApiError("Unknown permission: "+unknown))
+		return
+	}
+
+	err = h.rolesRepo.Update(c, id, models.Role{
+		Name:        updateRole.Name,
+		Permissions: updateRole.Permissions,
+	})
 	if err != nil {
 		logger.Error("Failed to update role", zap.Int("id", id), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
@@ -211,3 +224,45 @@ func (h *RolesHandler) Delete(c *gin.Context) {
 	logger.Info("Role deleted successfully", zap.Int("id", id))
 	c.Status(http.StatusOK)
 }
+
+
+// @Summary Get all permissions
+// @Description Get the list of permissions that can be assigned to roles
+// @Tags Roles
+// @Produce json
+// @Success 200 {array} models.Permission
+// @Failure 500 {object} models.ApiError
+// @Router /admin/permissions [get]
+func (h *RolesHandler) FindAllPermissions(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	permissions, err := h.rolesRepo.FindAllPermissions(c)
+	if err != nil {
+		logger.Error("Failed to load permissions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		return
+	}
+
+	logger.Info("Permissions loaded successfully", zap.Int("count", len(permissions)))
+	c.JSON(http.StatusOK, permissions)
+}
+
+// findUnknownPermission возвращает первое название права, которого нет в базе
+func (h *RolesHandler) findUnknownPermission(c *gin.Context, names []string) (string, error) {
+	permissions, err := h.rolesRepo.FindAllPermissions(c)
+	if err != nil {
+		return "", err
+	}
+
+	known := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		known[permission.Name] = true
+	}
+
+	for _, name := range names {
+		if !known[name] {
+			return name, nil
+		}
+	}
+	return "", nil
+}
@@ -1,15 +1,24 @@
 package admin
 
 import (
+	"fmt"
 	"net/http"
 	"ozinshe_production/logger"
 	"ozinshe_production/models"
 	"ozinshe_production/repositories"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
 type SearchHandler struct {
 	searchRepo *repositories.SearchRepository
 }
@@ -21,35 +30,80 @@ func NewSearchHandler(searchRepo *repositories.SearchRepository) *SearchHandler
 
 
 // SearchAll godoc
-// @Summary Search for movies, users, and categories
-// @Description Retrieve movies, users, and categories based on the search query
+// @Summary Search across all admin entities
+// @Description Search movies, seasons, episodes, categories, genres, ages, movie types and users.
+// @Description Returns up to limit results per type, links to the matching /admin routes and total match counts per type
 // @Tags search
 // @Accept json
 // @Produce json
 // @Param q query string true "Search query"
-// @Success 200 {array} repositories.SearchResult "List of search results"
+// @Param types query string false "Comma-separated types: Movie, Season, Episode, Category, Genre, Age, MovieType, User (default all)"
+// @Param limit query int false "Results per type, 1-50 (default 10)"
+// @Success 200 {object} repositories.SearchAllResult "Search results and facet counts"
 // @Failure 400 {object} models.ApiError "Invalid search query"
 // @Failure 500 {object} models.ApiError "Error during search"
 // @Router /admin/search [get]
 func (h *SearchHandler) SearchAll(c *gin.Context) {
     logger := logger.GetLogger()
 
-    query := c.Query("q")
+    query := strings.TrimSpace(c.Query("q"))
     if query == "" {
         logger.Warn("Empty search query received")
         c.JSON(http.StatusBadRequest, models.NewApiError("search request cannot be empty"))
         return
     }
 
-    logger.Info("Search query received", zap.String("query", query))
+    types, err := parseSearchTypes(c.Query("types"))
+    if err != nil {
+        logger.Warn("Invalid search types", zap.String("types", c.Query("types")))
+        c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+        return
+    }
+
+    limit := defaultSearchLimit
+    if limitStr := c.Query("limit"); limitStr != "" {
+        limit, err = strconv.Atoi(limitStr)
+        if err != nil || limit < 1 || limit > maxSearchLimit {
+            c.JSON(http.StatusBadRequest, models.NewApiError(fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit)))
+            return
+        }
+    }
+
+    logger.Info("Search query received", zap.String("query", query), zap.Strings("types", types))
 
-    results, err := h.searchRepo.SearchAll(c, query)
+    result, err := h.searchRepo.SearchAll(c, query, types, limit)
     if err != nil {
         logger.Error("Error during search", zap.Error(err))
         c.JSON(http.StatusInternalServerError, models.NewApiError("error during search"))
         return
     }
 
-    logger.Info("Search completed successfully", zap.Int("results_count", len(results)))
-    c.JSON(http.StatusOK, results)
-}
\ No newline at end of file
+    logger.Info("Search completed successfully", zap.Int("results_count", len(result.Results)))
+    c.JSON(http.StatusOK, result)
+}
+
+// parseSearchTypes разбирает список типов без учёта регистра. Пустая строка — все типы
+func parseSearchTypes(value string) ([]string, error) {
+    var types []string
+    if value == "" {
+        return types, nil
+    }
+
+    for _, part := range strings.Split(value, ",") {
+        part = strings.TrimSpace(part)
+        found := false
+        for _, searchType := range repositories.SearchTypes {
+            if strings.EqualFold(part, searchType) {
+                if !slices.Contains(types, searchType) {
+                    types = append(types, searchType)
+                }
+                found = true
+                break
+            }
+        }
+        if !found {
+            return nil, fmt.Errorf("unknown search type %q, expected one of %s", part, strings.Join(repositories.SearchTypes, ", "))
+        }
+    }
+    return types, nil
+}
@@ -0,0 +1,333 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"ozinshe_production/logger"
+	"ozinshe_production/models"
+	"ozinshe_production/repositories"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// VideoAssetsHandler управляет видео эпизодов (/admin/seasons/:seasonId/episodes/:episodeId/assets)
+// и полнометражных фильмов без сезонов (/admin/movies/:id/assets)
+type VideoAssetsHandler struct {
+	assetsRepo   *repositories.VideoAssetsRepository
+	episodesRepo *repositories.EpisodesRepository
+}
+
+func NewVideoAssetsHandler(assetsRepo *repositories.VideoAssetsRepository, episodesRepo *repositories.EpisodesRepository) *VideoAssetsHandler {
+	return &VideoAssetsHandler{assetsRepo: assetsRepo, episodesRepo: episodesRepo}
+}
+
+type videoAssetRequest struct {
+	Kind            string                  `json:"kind"`
+	DurationSeconds int                     `json:"duration_seconds"`
+	HlsURL          string                  `json:"hls_url"`
+	DashURL         string                  `json:"dash_url"`
+	Renditions      []models.VideoRendition `json:"renditions"`
+	Subtitles       []models.SubtitleTrack  `json:"subtitles"`
+	AudioTracks     []models.AudioTrack     `json:"audio_tracks"`
+}
+
+func (r videoAssetRequest) toAsset(owner models.VideoOwner) models.VideoAsset {
+	return models.VideoAsset{
+		VideoOwner:      owner,
+		Kind:            r.Kind,
+		DurationSeconds: r.DurationSeconds,
+		HlsURL:          r.HlsURL,
+		DashURL:         r.DashURL,
+		Renditions:      r.Renditions,
+		Subtitles:       r.Subtitles,
+		AudioTracks:     r.AudioTracks,
+	}
+}
+
+// FindAll godoc
+// @Summary      Get videos of an episode or a movie
+// @Description  Lists video assets with renditions, subtitles and audio tracks. The main video goes first
+// @Tags         video assets
+// @Produce      json
+// @Param        seasonId path int true "Season ID"
+// @Param        episodeId path int true "Episode ID"
+// @Param        id path int true "Movie ID, for a movie without seasons"
+// @Success      200 {array} models.VideoAsset "Video assets"
+// @Failure      400 {object} models.ApiError "Invalid ID"
+// @Failure      404 {object} models.ApiError "Episode not found"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Security     Bearer
+// @Router       /admin/seasons/{seasonId}/episodes/{episodeId}/assets [get]
+// @Router       /admin/movies/{id}/assets [get]
+func (h *VideoAssetsHandler) FindAll(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	owner, ok := h.resolveOwner(c)
+	if !ok {
+		return
+	}
+
+	assets, err := h.assetsRepo.FindAllByOwner(c, owner)
+	if err != nil {
+		logger.Error("Failed to load video assets", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load video assets"))
+		return
+	}
+
+	c.JSON(http.StatusOK, assets)
+}
+
+// FindById godoc
+// @Summary      Get a video
+// @Tags         video assets
+// @Produce      json
+// @Param        seasonId path int true "Season ID"
+// @Param        episodeId path int true "Episode ID"
+// @Param        id path int true "Movie ID, for a movie without seasons"
+// @Param        assetId path int true "Video asset ID"
+// @Success      200 {object} models.VideoAsset "Video asset"
+// @Failure      400 {object} models.ApiError "Invalid ID"
+// @Failure      404 {object} models.ApiError "Episode or video not found"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Security     Bearer
+// @Router       /admin/seasons/{seasonId}/episodes/{episodeId}/assets/{assetId} [get]
+// @Router       /admin/movies/{id}/assets/{assetId} [get]
+func (h *VideoAssetsHandler) FindById(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	owner, ok := h.resolveOwner(c)
+	if !ok {
+		return
+	}
+	id, ok := parseAssetId(c)
+	if !ok {
+		return
+	}
+
+	asset, err := h.assetsRepo.FindById(c, owner, id)
+	if errors.Is(err, repositories.ErrVideoAssetNotFound) {
+		c.JSON(http.StatusNotFound, models.NewApiError("Video not found"))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to load video asset", zap.Int("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load video asset"))
+		return
+	}
+
+	c.JSON(http.StatusOK, asset)
+}
+
+// Create godoc
+// @Summary      Add a video
+// @Description  Attaches a video to an episode or to a movie without seasons. An episode or movie has at most
+// @Description  one main video and any number of trailers. Needs hls_url or dash_url; subtitles are kk, ru or en,
+// @Description  one track per language. Audio tracks take any ISO 639 code (kk, tr, en-US), unique by language and label
+// @Tags         video assets
+// @Accept       json
+// @Produce      json
+// @Param        seasonId path int true "Season ID"
+// @Param        episodeId path int true "Episode ID"
+// @Param        id path int true "Movie ID, for a movie without seasons"
+// @Param        asset body videoAssetRequest true "Video, renditions, subtitles and audio tracks"
+// @Success      201 {object} map[string]int "ID of the created video asset"
+// @Failure      400 {object} models.ApiError "Invalid video or the movie has seasons"
+// @Failure      404 {object} models.ApiError "Episode or movie not found"
+// @Failure      409 {object} models.ApiError "Main video already exists"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Security     Bearer
+// @Router       /admin/seasons/{seasonId}/episodes/{episodeId}/assets [post]
+// @Router       /admin/movies/{id}/assets [post]
+func (h *VideoAssetsHandler) Create(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	owner, ok := h.resolveOwner(c)
+	if !ok {
+		return
+	}
+
+	asset, ok := bindVideoAsset(c, owner)
+	if !ok {
+		return
+	}
+
+	id, err := h.assetsRepo.Create(c, asset)
+	if !respondVideoAssetWriteError(c, err) {
+		return
+	}
+
+	logger.Info("Video asset created successfully", zap.Int("id", id), zap.String("kind", asset.Kind))
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// Update godoc
+// @Summary      Replace a video
+// @Description  Replaces the video and all of its renditions, subtitles and audio tracks
+// @Tags         video assets
+// @Accept       json
+// @Produce      json
+// @Param        seasonId path int true "Season ID"
+// @Param        episodeId path int true "Episode ID"
+// @Param        id path int true "Movie ID, for a movie without seasons"
+// @Param        assetId path int true "Video asset ID"
+// @Param        asset body videoAssetRequest true "Video, renditions, subtitles and audio tracks"
+// @Success      200 "Video updated"
+// @Failure      400 {object} models.ApiError "Invalid video"
+// @Failure      404 {object} models.ApiError "Episode or video not found"
+// @Failure      409 {object} models.ApiError "Main video already exists"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Security     Bearer
+// @Router       /admin/seasons/{seasonId}/episodes/{episodeId}/assets/{assetId} [put]
+// @Router       /admin/movies/{id}/assets/{assetId} [put]
+func (h *VideoAssetsHandler) Update(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	owner, ok := h.resolveOwner(c)
+	if !ok {
+		return
+	}
+	id, ok := parseAssetId(c)
+	if !ok {
+		return
+	}
+
+	asset, ok := bindVideoAsset(c, owner)
+	if !ok {
+		return
+	}
+	asset.Id = id
+
+	err := h.assetsRepo.Update(c, asset)
+	if !respondVideoAssetWriteError(c, err) {
+		return
+	}
+
+	logger.Info("Video asset updated successfully", zap.Int("id", id))
+	c.Status(http.StatusOK)
+}
+
+// Delete godoc
+// @Summary      Delete a video
+// @Tags         video assets
+// @Produce      json
+// @Param        seasonId path int true "Season ID"
+// @Param        episodeId path int true "Episode ID"
+// @Param        id path int true "Movie ID, for a movie without seasons"
+// @Param        assetId path int true "Video asset ID"
+// @Success      200 {object} map[string]string "Video deleted successfully"
+// @Failure      400 {object} models.ApiError "Invalid ID"
+// @Failure      404 {object} models.ApiError "Episode or video not found"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Security     Bearer
+// @Router       /admin/seasons/{seasonId}/episodes/{episodeId}/assets/{assetId} [delete]
+// @Router       /admin/movies/{id}/assets/{assetId} [delete]
+func (h *VideoAssetsHandler) Delete(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	owner, ok := h.resolveOwner(c)
+	if !ok {
+		return
+	}
+	id, ok := parseAssetId(c)
+	if !ok {
+		return
+	}
+
+	err := h.assetsRepo.Delete(c, owner, id)
+	if errors.Is(err, repositories.ErrVideoAssetNotFound) {
+		c.JSON(http.StatusNotFound, models.NewApiError("Video not found"))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to delete video asset", zap.Int("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to delete video asset"))
+		return
+	}
+
+	logger.Info("Video asset deleted successfully", zap.Int("id", id))
+	c.JSON(http.StatusOK, gin.H{"message": "Video deleted successfully"})
+}
+
+// resolveOwner определяет по маршруту, чьи это видео. Для эпизода проверяет, что он относится к сезону из пути
+func (h *VideoAssetsHandler) resolveOwner(c *gin.Context) (models.VideoOwner, bool) {
+	if c.Param("episodeId") == "" {
+		movieId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.NewApiError("Invalid movie id"))
+			return models.VideoOwner{}, false
+		}
+		return models.MovieVideoOwner(movieId), true
+	}
+
+	seasonId, err := strconv.Atoi(c.Param("seasonId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid seasonId"))
+		return models.VideoOwner{}, false
+	}
+	episodeId, err := strconv.Atoi(c.Param("episodeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid episodeId"))
+		return models.VideoOwner{}, false
+	}
+
+	episode, err := h.episodesRepo.FindById(c, episodeId)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && episode.SeasonID != seasonId) {
+		c.JSON(http.StatusNotFound, models.NewApiError("Episode not found"))
+		return models.VideoOwner{}, false
+	}
+	if err != nil {
+		logger.GetLogger().Error("Failed to find episode", zap.Int("episodeId", episodeId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to find episode"))
+		return models.VideoOwner{}, false
+	}
+	return models.EpisodeVideoOwner(episodeId), true
+}
+
+func parseAssetId(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("assetId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid assetId"))
+		return 0, false
+	}
+	return id, true
+}
+
+func bindVideoAsset(c *gin.Context, owner models.VideoOwner) (models.VideoAsset, bool) {
+	var req videoAssetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request format"))
+		return models.VideoAsset{}, false
+	}
+
+	asset := req.toAsset(owner)
+	if err := asset.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return models.VideoAsset{}, false
+	}
+	return asset, true
+}
+
+// respondVideoAssetWriteError отвечает на ошибку записи видео; false — ответ уже отправлен
+func respondVideoAssetWriteError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, repositories.ErrVideoAssetNotFound):
+		c.JSON(http.StatusNotFound, models.NewApiError("Video not found"))
+	case errors.Is(err, repositories.ErrMovieNotFound):
+		c.JSON(http.StatusNotFound, models.NewApiError("Movie not found"))
+	case errors.Is(err, repositories.ErrEpisodeNotFound):
+		c.JSON(http.StatusNotFound, models.NewApiError("Episode not found"))
+	case errors.Is(err, repositories.ErrMovieHasSeasons):
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+	case errors.Is(err, repositories.ErrMainVideoExists):
+		c.JSON(http.StatusConflict, models.NewApiError(err.Error()))
+	default:
+		logger.GetLogger().Error("Failed to save video asset", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to save video asset"))
+	}
+	return false
+}
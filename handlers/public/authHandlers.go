@@ -2,29 +2,36 @@ package public
 
 import (
 
+	"errors"
 	"net/http"
 	"ozinshe_production/config"
 	"ozinshe_production/models"
 	"ozinshe_production/repositories"
 	"ozinshe_production/logger"
 
-	"strconv"
-	"time"
 	"net/mail"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 
 	"golang.org/x/crypto/bcrypt"
 	"go.uber.org/zap"
 )
 
 type AuthHandlers struct {
-	userRepo *repositories.UsersRepository
+	userRepo          *repositories.UsersRepository
+	revokedTokensRepo *repositories.RevokedTokensRepository
+	refreshTokensRepo *repositories.RefreshTokensRepository
 }
 
-func NewAuthHandlers(userRepo *repositories.UsersRepository) *AuthHandlers {
-	return &AuthHandlers{userRepo: userRepo}
+func NewAuthHandlers(userRepo *repositories.UsersRepository,
+					 revokedTokensRepo *repositories.RevokedTokensRepository,
+					 refreshTokensRepo *repositories.RefreshTokensRepository) *AuthHandlers {
+	return &AuthHandlers{
+		userRepo:          userRepo,
+		revokedTokensRepo: revokedTokensRepo,
+		refreshTokensRepo: refreshTokensRepo,
+	}
 }
 
 
@@ -41,6 +48,16 @@ type SignInRequest struct {
 }
 
 
+type RefreshRequest struct {
+	RefreshToken 	string `json:"refresh_token" binding:"required"`
+}
+
+
+type SignOutRequest struct {
+	RefreshToken 	string `json:"refresh_token"`
+}
+
+
 // SignUp godoc
 // @Summary      User Registration
 // @Description  Registers a new user by providing an email, password, and password confirmation
@@ -48,7 +65,7 @@ type SignInRequest struct {
 // @Accept       json
 // @Produce      json
 // @Param        request body public.SignUpRequest true "User registration request"
-// @Success      200 {object} object{id=int} "User successfully created"
+// @Success      200 {object} tokenResponse "User successfully created, access and refresh tokens issued"
 // @Failure      400 {object} models.ApiError "Validation error: invalid email, password mismatch, or weak password"
 // @Failure      500 {object} models.ApiError "Server error: failed to hash password or create user"
 // @Router       /auth/signUp [post]
@@ -102,13 +119,7 @@ func (h *AuthHandlers) SignUp(c *gin.Context) {
 		return
 	}
 
-	claims := jwt.RegisteredClaims {
-		Subject: strconv.Itoa(id),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.Config.JwtExpiresIn)),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(config.Config.JwtSecretKey))
+	tokens, err := h.issueTokens(c, id)
 	if err != nil {
 		logger.Error("Couldn't generate JWT token", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't generate JWT token"))
@@ -116,19 +127,19 @@ func (h *AuthHandlers) SignUp(c *gin.Context) {
 	}
 
 	logger.Info("User successfully registered", zap.String("email", request.Email), zap.Int("user_id", id))
-	c.JSON(http.StatusOK, gin.H{"token": tokenString})
+	c.JSON(http.StatusOK, tokens)
 }
 
 
 
 // SignIn godoc
 // @Summary      User Sign In
-// @Description  Authenticates a user by verifying the email and password, and returns a JWT token
+// @Description  Authenticates a user by verifying the email and password, and returns a short-lived JWT access token with a refresh token
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        request body public.SignInRequest true "User sign-in request"
-// @Success      200 {object} object{token=string} "JWT token successfully generated"
+// @Success      200 {object} tokenResponse "JWT and refresh tokens successfully generated"
 // @Failure      400 {object} models.ApiError "Invalid payload"
 // @Failure      401 {object} models.ApiError "Invalid credentials: wrong email or password"
 // @Failure      500 {object} models.ApiError "Internal server error: failed to generate JWT token"
@@ -156,13 +167,7 @@ func (h *AuthHandlers) SignIn(c *gin.Context) {
 		return
 	}
 
-	claims := jwt.RegisteredClaims {
-		Subject: strconv.Itoa(user.Id),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.Config.JwtExpiresIn)),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(config.Config.JwtSecretKey))
+	tokens, err := h.issueTokens(c, user.Id)
 	if err != nil {
 		logger.Error("Error generating JWT token", zap.String("email", request.Email), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't generate JWT token"))
@@ -170,25 +175,109 @@ func (h *AuthHandlers) SignIn(c *gin.Context) {
 	}
 
 	logger.Info("User successfully signed in", zap.String("email", request.Email))
-	c.JSON(http.StatusOK, gin.H{"token": tokenString})
+	c.JSON(http.StatusOK, tokens)
+}
+
+
+
+// Refresh godoc
+// @Summary      Refresh Tokens
+// @Description  Exchanges a refresh token for a new access token and a new refresh token. Each refresh token can be used once; reusing one revokes every token issued from the same sign-in
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body public.RefreshRequest true "Refresh token"
+// @Success      200 {object} tokenResponse "New access and refresh tokens"
+// @Failure      400 {object} models.ApiError "Invalid payload"
+// @Failure      401 {object} models.ApiError "Refresh token is invalid, expired or reused"
+// @Failure      500 {object} models.ApiError "Internal server error"
+// @Router       /auth/refresh [post]
+func (h *AuthHandlers) Refresh(c *gin.Context) {
+	logger := logger.GetLogger()
+	var request RefreshRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Invalid refresh request", zap.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid payload"))
+		return
+	}
+
+	refreshToken, refreshTokenHash, err := generateRefreshToken()
+	if err != nil {
+		logger.Error("Couldn't generate refresh token", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't generate refresh token"))
+		return
+	}
+
+	expiresAt := time.Now().Add(config.Config.JwtRefreshExpiresIn)
+	userId, err := h.refreshTokensRepo.Rotate(c, hashRefreshToken(request.RefreshToken), refreshTokenHash, expiresAt)
+	if errors.Is(err, repositories.ErrRefreshTokenReused) {
+		logger.Warn("Refresh token reuse detected, token family revoked", zap.Int("userId", userId))
+		c.JSON(http.StatusUnauthorized, models.NewApiError("Refresh token has already been used"))
+		return
+	}
+	if errors.Is(err, repositories.ErrRefreshTokenInvalid) {
+		logger.Warn("Invalid refresh token")
+		c.JSON(http.StatusUnauthorized, models.NewApiError("Invalid refresh token"))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to rotate refresh token", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't refresh token"))
+		return
+	}
+
+	accessToken, err := generateJWT(userId)
+	if err != nil {
+		logger.Error("Couldn't generate JWT token", zap.Int("userId", userId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Couldn't generate JWT token"))
+		return
+	}
+
+	logger.Info("Tokens refreshed", zap.Int("userId", userId))
+	c.JSON(http.StatusOK, tokenResponse{Token: accessToken, RefreshToken: refreshToken})
 }
 
 
 
 // SignOut godoc
 // @Summary      User Sign Out
-// @Description  Invalidates the user's current session (requires a valid JWT token)
+// @Description  Revokes the JWT token used for the request so it can no longer be used, even before it expires. If a refresh token is passed, every refresh token issued from the same sign-in is revoked too
 // @Tags         auth
 // @Accept       json
 // @Produce      json
+// @Param        request body public.SignOutRequest false "Refresh token to revoke"
 // @Success      200 "OK"
 // @Failure      401 {object} models.ApiError "Authorization header required"
+// @Failure      500 {object} models.ApiError "Failed to revoke token"
 // @Router       /auth/signOut [post]
 // @Security Bearer
 func (h *AuthHandlers) SignOut(c *gin.Context) {
 	logger := logger.GetLogger()
-	logger.Info("User successfully signed out")
 
+	userId := c.GetInt("userId")
+	tokenId := c.GetString("tokenId")
+	expiresAt := c.GetTime("tokenExpiresAt")
+
+	err := h.revokedTokensRepo.Revoke(c, tokenId, userId, expiresAt)
+	if err != nil {
+		logger.Error("Failed to revoke token", zap.Int("userId", userId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to revoke token"))
+		return
+	}
+
+	// Тело запроса необязательно: без refresh-токена отзывается только access-токен
+	var request SignOutRequest
+	_ = c.ShouldBindJSON(&request)
+	if request.RefreshToken != "" {
+		err = h.refreshTokensRepo.RevokeFamily(c, hashRefreshToken(request.RefreshToken), userId)
+		if err != nil {
+			logger.Error("Failed to revoke refresh tokens", zap.Int("userId", userId), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to revoke refresh token"))
+			return
+		}
+	}
+
+	logger.Info("User successfully signed out", zap.Int("userId", userId))
 	c.Status(http.StatusOK)
 }
 
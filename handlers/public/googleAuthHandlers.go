@@ -7,12 +7,8 @@ import (
 	"net/http"
 	"ozinshe_production/config"
 	"ozinshe_production/models"
-	"strconv"
-
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/oauth2"
 )
 
@@ -75,22 +71,12 @@ func (h *AuthHandlers) GoogleCallback(c *gin.Context) {
 	}
 
 
-	tokenString, err := generateJWT(user.Id)
+	tokens, err := h.issueTokens(c, user.Id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to generate token"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": tokenString, "user": googleUser})
+	c.JSON(http.StatusOK, gin.H{"token": tokens.Token, "refresh_token": tokens.RefreshToken, "user": googleUser})
 }
 
-
-func generateJWT(userId int) (string, error) {
-	claims := jwt.RegisteredClaims{
-		Subject: strconv.Itoa(userId),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.Config.JwtExpiresIn)),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(config.Config.JwtSecretKey))
-}
\ No newline at end of file
@@ -1,11 +1,15 @@
 package public
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"ozinshe_production/logger"
 	"ozinshe_production/models"
 	"ozinshe_production/repositories"
+	"ozinshe_production/storage"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -17,6 +21,7 @@ type HomepageHandler struct {
 	genresRepo 			*repositories.GenresRepository
 	categoriesRepo  	*repositories.CategoriesRepository
 	agesRepo 			*repositories.AgesRepository
+	storage 			storage.Storage
 }
 
 func NewHomepageHandler(hompageRepo 	*repositories.HomepageRepository,
@@ -24,146 +29,303 @@ func NewHomepageHandler(hompageRepo 	*repositories.HomepageRepository,
 					  	genresRepo 		*repositories.GenresRepository,
 						categoriesRepo 	*repositories.CategoriesRepository,
 					  	agesRepo 		*repositories.AgesRepository,
+						store 			storage.Storage,
 					  	) *HomepageHandler {
 	return &HomepageHandler{hompageRepo: 	hompageRepo,
 							moviesRepo:		moviesRepo,
 							genresRepo: 	genresRepo,
 							categoriesRepo: categoriesRepo,
 							agesRepo: 		agesRepo,
+							storage: 		store,
 						}
 }
 
 // GetMainScreen godoc
 // @Summary      Get Main Screen Data
-// @Description  Retrieves the main screen data, including recommended movies, categories, genres, and age ratings
+// @Description  Retrieves the main screen: rails configured under /admin/homepage in their order, genres and age ratings.
+// @Description  Rails contain lightweight movie cards; empty rails and rails outside their visibility window are omitted.
+// @Description  Without configured rails the default layout is returned: recommended movies and the newest movies of every category.
+// @Description  A section that fails to load is left out and listed in unavailable_sections
 // @Tags         homepage
 // @Accept       json
 // @Produce      json
-// @Success      200 {object} gin.H "Main screen data including recommended movies, categories, genres, and ages"
+// @Success      200 {object} gin.H "Main screen data including rails, genres, and ages"
 // @Failure      500 {object} models.ApiError "Server error: failed to load data"
 // @Router       /homepage [get]
 func (h *HomepageHandler) GetMainScreen(c *gin.Context) {
 	logger := logger.GetLogger()
 
-	// Получаем рекомендованные фильмы
-	recommended, err := h.hompageRepo.GetRecommendedMovies(c)
-	if err != nil {
-		logger.Error("Failed to load recommended movies", zap.Error(err))
-		c.Status(http.StatusInternalServerError)
-		return
+	// Ошибка одного раздела не должна ломать всю страницу: раздел отдаётся пустым
+	unavailable := make([]string, 0)
+	sections := 0
+	section := func(name string, err error) bool {
+		sections++
+		if err != nil {
+			logger.Error("Failed to load homepage section", zap.String("section", name), zap.Error(err))
+			unavailable = append(unavailable, name)
+			return false
+		}
+		return true
 	}
 
-	// Получаем категории
-	categories, err := h.categoriesRepo.FindAll(c)
-	if err != nil {
-		logger.Error("Failed to load categories", zap.Error(err))
-		c.Status(http.StatusInternalServerError)
-		return
+	rails := []models.HomepageRailView{}
+	configured, err := h.hompageRepo.FindVisibleRails(c)
+	if section("rails", err) {
+		if len(configured) == 0 {
+			rails = h.defaultRails(c, section)
+		} else {
+			rails = h.renderRails(c, configured, section)
+		}
 	}
 
-	// Получаем все доступные жанры
 	genres, err := h.genresRepo.FindAll(c)
-	if err != nil {
-		logger.Error("Failed to load genres", zap.Error(err))
-		c.Status(http.StatusInternalServerError)
-		return
+	if !section("genres", err) {
+		genres = []models.Genre{}
 	}
 
-	// Получаем возрастные рейтинги
 	ages, err := h.agesRepo.FindAll(c)
-	if err != nil {
-		logger.Error("Failed to load ages", zap.Error(err))
-		c.Status(http.StatusInternalServerError)
-		return
+	if !section("ages", err) {
+		ages = []models.Ages{}
 	}
 
-	// Получаем фильмы по категориям, жанрам и возрастам
-	moviesByCategory := make(map[string][]models.Movie)
-	for _, category := range categories {
-		filters := models.Moviesfilters{
-			CategoryIds: fmt.Sprint(category.Id),
-		}
+	if len(unavailable) == sections {
+		c.JSON(http.StatusInternalServerError, models.NewApiError("failed to load main screen"))
+		return
+	}
 
-		movies, err := h.moviesRepo.FindAll(c, filters)
-		if err != nil {
-			logger.Error("Failed to load movies for category", zap.String("category", category.Title), zap.Error(err))
-			return
-		}
-		moviesByCategory[category.Title] = movies
+	if err := h.resolveMainScreenMedia(c, rails, genres, ages); err != nil {
+		logger.Error("Failed to build media URLs", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("failed to load main screen"))
+		return
 	}
 
 	// Формируем JSON-ответ
 	response := gin.H{
-		"recommended":       	recommended,
-		"movies_by_category":	moviesByCategory,
-		"genres":            	genres,
-		"ages":              	ages,
+		"rails":                rails,
+		"genres":               genres,
+		"ages":                 ages,
+		"unavailable_sections": unavailable,
 	}
 
-	logger.Info("Main screen data loaded successfully", zap.Int("recommended_count", len(recommended)))
+	logger.Info("Main screen data loaded successfully", zap.Int("rails_count", len(rails)),
+		zap.Strings("unavailable_sections", unavailable))
 	c.JSON(http.StatusOK, response)
 }
 
+// resolveMainScreenMedia заменяет ключи обложек и постеров ссылками хранилища
+func (h *HomepageHandler) resolveMainScreenMedia(c *gin.Context, rails []models.HomepageRailView, genres []models.Genre, ages []models.Ages) error {
+	for _, rail := range rails {
+		if err := storage.ResolveCovers(c, h.storage, rail.Movies); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	for i := range genres {
+		if genres[i].PosterUrl, err = storage.ResolveURL(c, h.storage, genres[i].PosterUrl); err != nil {
+			return err
+		}
+	}
+	for i := range ages {
+		if ages[i].PosterUrl, err = storage.ResolveURL(c, h.storage, ages[i].PosterUrl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderRails заполняет ленты фильмами: по одному запросу на каждый тип лент
+func (h *HomepageHandler) renderRails(c *gin.Context, rails []models.HomepageRail, section func(string, error) bool) []models.HomepageRailView {
+	byType := make(map[string][]models.HomepageRail)
+	for _, rail := range rails {
+		byType[rail.Type] = append(byType[rail.Type], rail)
+	}
+
+	cards := make(map[int][]models.MovieCard, len(rails))
+	for _, railType := range []string{models.RailTypeCategory, models.RailTypeGenre, models.RailTypeHandPicked,
+		models.RailTypeContinueWatching, models.RailTypeNewReleases} {
+		if len(byType[railType]) == 0 {
+			continue
+		}
+		movies, err := h.hompageRepo.FindRailMovies(c, railType, byType[railType], c.GetInt("userId"))
+		if !section("rails:"+railType, err) {
+			continue
+		}
+		for id, list := range movies {
+			cards[id] = list
+		}
+	}
+
+	views := make([]models.HomepageRailView, 0, len(rails))
+	for _, rail := range rails {
+		if len(cards[rail.Id]) == 0 {
+			continue
+		}
+		views = append(views, models.HomepageRailView{Id: rail.Id, Type: rail.Type, Title: rail.Title, Movies: cards[rail.Id]})
+	}
+	return views
+}
+
+// defaultRails собирает главную страницу, пока редакторы не настроили ленты:
+// рекомендации и новинки каждой категории. У таких лент id равен 0
+func (h *HomepageHandler) defaultRails(c *gin.Context, section func(string, error) bool) []models.HomepageRailView {
+	views := make([]models.HomepageRailView, 0)
+
+	recommended, err := h.hompageRepo.GetRecommendedCards(c, models.DefaultRailSize)
+	if section("recommended", err) && len(recommended) > 0 {
+		views = append(views, models.HomepageRailView{Type: models.RailTypeHandPicked, Title: "Recommended", Movies: recommended})
+	}
+
+	categoryRails, err := h.hompageRepo.GetCategoryRails(c, models.DefaultRailSize)
+	if section("movies_by_category", err) {
+		for _, rail := range categoryRails {
+			views = append(views, models.HomepageRailView{Type: models.RailTypeCategory, Title: rail.Title, Movies: rail.Movies})
+		}
+	}
+
+	return views
+}
+
 // SearchMovies godoc
 // @Summary      Search Movies
-// @Description  Searches for movies based on a query string
+// @Description  Full-text search over title, keywords, director, producer and description (Kazakh, Russian, English).
+// @Description  Words match by prefix, typos in the title are tolerated, results are ordered by relevance
+// @Description  and carry highlighted snippets with matches wrapped in <b></b>
 // @Tags         homepage
 // @Accept       json
 // @Produce      json
 // @Param        query query string true "Search query"
+// @Param        limit query int false "Maximum number of results, 1-100 (default 20)"
 // @Success      200 {object} gin.H "Search results with matching movies"
-// @Failure      400 {object} models.ApiError "Bad request: empty search query"
+// @Failure      400 {object} models.ApiError "Bad request: empty search query or invalid limit"
 // @Failure      500 {object} models.ApiError "Server error: failed to search movies"
 // @Router       /search [get]
 func (h *HomepageHandler) SearchMovies(c *gin.Context) {
 	logger := logger.GetLogger()
-	query := c.Query("query")
+	query := strings.TrimSpace(c.Query("query"))
 
 	if query == "" {
 		c.JSON(http.StatusBadRequest, models.NewApiError("Empty search query"))
 		return
 	}
 
-	movies, err := h.moviesRepo.SearchMovies(c, query)
+	limit := models.DefaultMoviesLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > models.MaxMoviesLimit {
+			c.JSON(http.StatusBadRequest, models.NewApiError(fmt.Sprintf("limit must be between 1 and %d", models.MaxMoviesLimit)))
+			return
+		}
+	}
+
+	movies, err := h.moviesRepo.SearchMovies(c, query, limit)
 	if err != nil {
 		logger.Error("Failed to search movies", zap.Error(err))
 		c.Status(http.StatusInternalServerError)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"search_results": movies})
+	cards := make([]models.MovieCard, len(movies))
+	for i, movie := range movies {
+		cards[i] = newMovieCard(movie.Movie)
+	}
+	if err := storage.ResolveCovers(c, h.storage, cards); err != nil {
+		logger.Error("Failed to build media URLs", zap.Error(err))
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	results := make([]models.MovieCardSearchResult, len(movies))
+	for i, movie := range movies {
+		results[i] = models.MovieCardSearchResult{MovieCard: cards[i], Rank: movie.Rank, Highlights: movie.Highlights}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"search_results": results})
 }
 
 // GetMoviesByCategory godoc
 // @Summary      Get Movies by Category
-// @Description  Retrieves movies based on the provided category ID
+// @Description  Retrieves a page of movies for the provided category ID with the total count
 // @Tags         homepage
 // @Accept       json
 // @Produce      json
 // @Param        category_id path string true "Category ID"
-// @Success      200 {object} gin.H "Movies belonging to the specified category"
-// @Failure      400 {object} models.ApiError "Bad request: category ID is required"
+// @Param        sort query string false "Sort key: title, release_year, created_at (default)"
+// @Param        order query string false "Sort order: asc or desc"
+// @Param        limit query int false "Page size, 1-100 (default 20)"
+// @Param        cursor query string false "next_cursor from the previous page"
+// @Success      200 {object} models.MovieCardsPage "Movie cards belonging to the specified category"
+// @Failure      400 {object} models.ApiError "Bad request: invalid category ID or pagination parameters"
 // @Failure      500 {object} models.ApiError "Server error: failed to load movies for category"
 // @Router       /search/{category_id} [get]
 func (h *HomepageHandler) GetMoviesByCategory(c *gin.Context) {
 	logger := logger.GetLogger()
-	categoryID := c.Param("category_id")
-
-	if categoryID == "" {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Category id required"))
+	categoryID, err := strconv.Atoi(c.Param("category_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid category id"))
 		return
 	}
 
 	filters := models.Moviesfilters{
-		CategoryIds: categoryID,
+		CategoryIds: []int{categoryID},
+		Statuses:    []string{models.MovieStatusPublished},
+		Sort:        c.Query("sort"),
+		Order:       c.Query("order"),
+		Cursor:      c.Query("cursor"),
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.NewApiError("Invalid limit"))
+			return
+		}
+		filters.Limit = limit
 	}
 
-	movies, err := h.moviesRepo.FindAll(c, filters)
+	if err := filters.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
+	}
+
+	page, err := h.moviesRepo.FindAll(c, filters)
+	if errors.Is(err, repositories.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to load movies by category", zap.Error(err))
 		c.Status(http.StatusInternalServerError)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"movies": movies})
+	cards := models.MovieCardsPage{
+		Movies:     make([]models.MovieCard, len(page.Movies)),
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+	}
+	for i, movie := range page.Movies {
+		cards.Movies[i] = newMovieCard(movie)
+	}
+	if err := storage.ResolveCovers(c, h.storage, cards.Movies); err != nil {
+		logger.Error("Failed to build media URLs", zap.Error(err))
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, cards)
+}
+
+// newMovieCard оставляет от фильма только карточку для зрителя. Cover — ключ в хранилище,
+// ссылку на него подставляет storage.ResolveCovers
+func newMovieCard(movie models.Movie) models.MovieCard {
+	return models.MovieCard{
+		Id:          movie.Id,
+		Title:       movie.Title,
+		Cover:       movie.Media.Cover,
+		ReleaseYear: movie.ReleaseYear,
+		MovieTypeId: movie.MovieTypeId,
+		MovieType:   movie.MovieType,
+	}
 }
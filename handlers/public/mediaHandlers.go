@@ -0,0 +1,104 @@
+package public
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"mime"
+	"net/http"
+	"ozinshe_production/imaging"
+	"ozinshe_production/logger"
+	"ozinshe_production/models"
+	"ozinshe_production/storage"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Содержимое по ключу не меняется, см. imaging.Cache
+const mediaCacheControl = "public, max-age=31536000, immutable"
+
+type MediaHandler struct {
+	cache *imaging.Cache
+}
+
+func NewMediaHandler(cache *imaging.Cache) *MediaHandler {
+	return &MediaHandler{cache: cache}
+}
+
+// ServeMedia godoc
+// @Summary      Get a media file
+// @Description  Serves an uploaded cover, screenshot or poster with ETag, Cache-Control and Range support.
+// @Description  With w and/or format returns a downscaled copy, generated on the first request and cached on disk
+// @Tags         media
+// @Produce      image/jpeg,image/png,image/webp
+// @Param        key    path  string false "Storage key"
+// @Param        w      query int    false "Width: 160, 320, 480, 640, 960, 1280 or 1920. Images are never upscaled"
+// @Param        format query string false "jpeg, png or webp (default: format of the original)"
+// @Success      200 {file} binary "Media file"
+// @Success      206 {file} binary "Requested range"
+// @Success      304 "Not modified"
+// @Failure      400 {object} models.ApiError "Invalid w or format"
+// @Failure      404 {object} models.ApiError "Media not found"
+// @Failure      415 {object} models.ApiError "Media is not an image that can be resized"
+// @Failure      500 {object} models.ApiError "Failed to load media"
+// @Router       /media/{key} [get]
+func (h *MediaHandler) ServeMedia(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	variant, err := imaging.ParseVariant(c.Query("w"), c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
+	}
+
+	file, err := h.cache.Original(c, key)
+	if err == nil && !variant.IsOriginal() {
+		file.Close()
+		file, err = h.cache.Variant(c, key, variant)
+	}
+	switch {
+	case errors.Is(err, storage.ErrNotFound), errors.Is(err, storage.ErrInvalidKey):
+		c.JSON(http.StatusNotFound, models.NewApiError("Media not found"))
+		return
+	case errors.Is(err, imaging.ErrUnsupportedFormat), errors.Is(err, imaging.ErrInvalidImage), errors.Is(err, imaging.ErrImageTooLarge):
+		c.JSON(http.StatusUnsupportedMediaType, models.NewApiError("Media can't be resized: "+err.Error()))
+		return
+	case err != nil:
+		logger.Error("Failed to load media", zap.String("key", key), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load media"))
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		logger.Error("Failed to stat media", zap.String("key", key), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load media"))
+		return
+	}
+
+	// ETag различает оригинал и варианты и меняется, если файл в кэше пересоздан
+	name := fnv.New32a()
+	name.Write([]byte(file.Name()))
+	c.Header("ETag", fmt.Sprintf(`"%x-%x-%x"`, name.Sum32(), info.ModTime().UnixNano(), info.Size()))
+	c.Header("Cache-Control", mediaCacheControl)
+	c.Header("Content-Type", mediaContentType(file.Name()))
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	// ServeContent отвечает на Range, If-None-Match и If-Range
+	http.ServeContent(c.Writer, c.Request, "", info.ModTime(), file)
+}
+
+// mediaContentType берёт тип по расширению. Файлы, загруженные до проверки содержимого, могут
+// называться как угодно, поэтому всё, кроме растровых изображений, отдаётся как бинарные данные
+func mediaContentType(name string) string {
+	contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(name)))
+	if !strings.HasPrefix(contentType, "image/") || strings.HasPrefix(contentType, "image/svg") {
+		return "application/octet-stream"
+	}
+	return contentType
+}
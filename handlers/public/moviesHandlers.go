@@ -0,0 +1,230 @@
+package public
+
+import (
+	"net/http"
+	"ozinshe_production/logger"
+	"ozinshe_production/models"
+	"ozinshe_production/repositories"
+	"ozinshe_production/storage"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const relatedMoviesLimit = 12
+
+type MoviesHandler struct {
+	moviesRepo    *repositories.MoviesRepository
+	watchlistRepo *repositories.WatchlistRepository
+	playbackRepo  *repositories.PlaybackRepository
+	assetsRepo    *repositories.VideoAssetsRepository
+	storage       storage.Storage
+}
+
+func NewMoviesHandler(
+	moviesRepo *repositories.MoviesRepository,
+	watchlistRepo *repositories.WatchlistRepository,
+	playbackRepo *repositories.PlaybackRepository,
+	assetsRepo *repositories.VideoAssetsRepository,
+	store storage.Storage) *MoviesHandler {
+	return &MoviesHandler{
+		moviesRepo:    moviesRepo,
+		watchlistRepo: watchlistRepo,
+		playbackRepo:  playbackRepo,
+		assetsRepo:    assetsRepo,
+		storage:       store,
+	}
+}
+
+// GetMovie godoc
+// @Summary      Get movie details
+// @Description  Returns a published movie with genres, categories, ages, media URLs and seasons with episodes sorted
+// @Description  by number. Videos with renditions, subtitles and audio tracks are listed in assets of the movie
+// @Description  (movies without seasons) or of each episode. Also tells whether the movie is in the user's watchlist,
+// @Description  what the user has watched and lists related titles that share genres or categories
+// @Tags         movies
+// @Produce      json
+// @Param        id path int true "Movie ID"
+// @Success      200 {object} models.MovieDetail "Movie details"
+// @Failure      400 {object} models.ApiError "Invalid movie ID"
+// @Failure      404 {object} models.ApiError "Movie not found"
+// @Failure      500 {object} models.ApiError "Failed to load movie"
+// @Security     Bearer
+// @Router       /movies/{id} [get]
+func (h *MoviesHandler) GetMovie(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid movie ID"))
+		return
+	}
+
+	movie, err := h.moviesRepo.FindById(c, id)
+	if err != nil {
+		logger.Error("Failed to load movie", zap.Int("movie_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load movie"))
+		return
+	}
+	if movie.Id == 0 || movie.Status != models.MovieStatusPublished {
+		c.JSON(http.StatusNotFound, models.NewApiError("Movie not found"))
+		return
+	}
+
+	if err := storage.ResolveMovieMedia(c, h.storage, &movie.Media); err != nil {
+		logger.Error("Failed to build media URLs", zap.Int("movie_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load movie"))
+		return
+	}
+
+	detail := newMovieDetail(movie)
+	userId := c.GetInt("userId")
+
+	assets, err := h.assetsRepo.FindAllByMovie(c, id)
+	if err != nil {
+		logger.Error("Failed to load video assets", zap.Int("movie_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load movie"))
+		return
+	}
+	applyVideoAssets(&detail, assets)
+
+	detail.InWatchlist, err = h.watchlistRepo.IsInWatchlist(c, userId, id)
+	if err != nil {
+		logger.Error("Failed to check watchlist", zap.Int("user_id", userId), zap.Int("movie_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load movie"))
+		return
+	}
+
+	progress, err := h.playbackRepo.FindMovieProgress(c, userId, id)
+	if err != nil {
+		logger.Error("Failed to load movie progress", zap.Int("user_id", userId), zap.Int("movie_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load movie"))
+		return
+	}
+	applyWatchState(&detail, progress)
+
+	detail.Related, err = h.moviesRepo.FindRelated(c, id, relatedMoviesLimit)
+	if err != nil {
+		logger.Error("Failed to load related movies", zap.Int("movie_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load movie"))
+		return
+	}
+	if err := storage.ResolveCovers(c, h.storage, detail.Related); err != nil {
+		logger.Error("Failed to build media URLs", zap.Int("movie_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load movie"))
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// newMovieDetail оставляет в фильме только то, что можно показать зрителю. Порядок вложенных списков
+// задаёт MoviesRepository.FindById, ссылки на медиафайлы уже должны быть подставлены
+func newMovieDetail(movie models.Movie) models.MovieDetail {
+	detail := models.MovieDetail{
+		Id:          movie.Id,
+		Title:       movie.Title,
+		Description: movie.Description,
+		ReleaseYear: movie.ReleaseYear,
+		Runtime:     movie.Runtime,
+		Keywords:    movie.KeyWords,
+		Director:    movie.Director,
+		Producer:    movie.Producer,
+		MovieType:   models.NamedRef{Id: movie.MovieTypeId, Title: movie.MovieType},
+		Genres:      make([]models.NamedRef, 0, len(movie.Genres)),
+		Categories:  make([]models.NamedRef, 0, len(movie.Categories)),
+		Ages:        make([]models.NamedRef, 0, len(movie.Ages)),
+		Screenshots: make([]string, 0, len(movie.Media.Screenshots)),
+		Gallery:     make([]models.MovieMediaItem, 0, len(movie.Media.Items)),
+		Assets:      []models.VideoAsset{},
+		Seasons:     make([]models.SeasonDetail, 0, len(movie.Seasons)),
+	}
+	if detail.Keywords == nil {
+		detail.Keywords = []string{}
+	}
+
+	for _, genre := range movie.Genres {
+		detail.Genres = append(detail.Genres, models.NamedRef{Id: genre.Id, Title: genre.Title})
+	}
+	for _, category := range movie.Categories {
+		detail.Categories = append(detail.Categories, models.NamedRef{Id: category.Id, Title: category.Title})
+	}
+	for _, age := range movie.Ages {
+		detail.Ages = append(detail.Ages, models.NamedRef{Id: age.Id, Title: age.Title})
+	}
+
+	detail.Cover = movie.Media.Cover
+	detail.Screenshots = append(detail.Screenshots, movie.Media.Screenshots...)
+	detail.Gallery = append(detail.Gallery, movie.Media.Items...)
+
+	for _, season := range movie.Seasons {
+		seasonDetail := models.SeasonDetail{
+			Id:       season.Id,
+			Number:   season.Number,
+			Episodes: make([]models.EpisodeDetail, 0, len(season.Episodes)),
+		}
+		for _, episode := range season.Episodes {
+			seasonDetail.Episodes = append(seasonDetail.Episodes, models.EpisodeDetail{
+				Id:       episode.Id,
+				Number:   episode.Number,
+				VideoURL: episode.VideoURL,
+				Assets:   []models.VideoAsset{},
+			})
+		}
+		detail.Seasons = append(detail.Seasons, seasonDetail)
+	}
+
+	return detail
+}
+
+// applyVideoAssets раскладывает видео по фильму и эпизодам; порядок задаёт VideoAssetsRepository.FindAllByMovie
+func applyVideoAssets(detail *models.MovieDetail, assets []models.VideoAsset) {
+	byEpisode := make(map[int][]models.VideoAsset)
+	for _, asset := range assets {
+		if asset.EpisodeId != nil {
+			byEpisode[*asset.EpisodeId] = append(byEpisode[*asset.EpisodeId], asset)
+		} else {
+			detail.Assets = append(detail.Assets, asset)
+		}
+	}
+
+	for s := range detail.Seasons {
+		for e := range detail.Seasons[s].Episodes {
+			episode := &detail.Seasons[s].Episodes[e]
+			episode.Assets = append(episode.Assets, byEpisode[episode.Id]...)
+		}
+	}
+}
+
+// applyWatchState переносит отметки просмотра в карточку; сезоны уже должны быть упорядочены
+func applyWatchState(detail *models.MovieDetail, progress []models.PlaybackProgress) {
+	var episodes []models.EpisodeRef
+	for _, season := range detail.Seasons {
+		for _, episode := range season.Episodes {
+			episodes = append(episodes, models.EpisodeRef{
+				EpisodeId:     episode.Id,
+				EpisodeNumber: episode.Number,
+				SeasonId:      season.Id,
+				SeasonNumber:  season.Number,
+			})
+		}
+	}
+
+	state := models.NewMovieWatchState(detail.Id, episodes, progress)
+	detail.Watched = state.Watched
+	detail.PositionSeconds = state.PositionSeconds
+	detail.DurationSeconds = state.DurationSeconds
+
+	i := 0
+	for s := range detail.Seasons {
+		for e := range detail.Seasons[s].Episodes {
+			episodeState := state.Episodes[i]
+			episode := &detail.Seasons[s].Episodes[e]
+			episode.Watched = episodeState.Watched
+			episode.PositionSeconds = episodeState.PositionSeconds
+			episode.DurationSeconds = episodeState.DurationSeconds
+			i++
+		}
+	}
+}
@@ -3,6 +3,7 @@ package public
 import (
 	"net/http"
 	"ozinshe_production/logger"
+	"ozinshe_production/middlewares"
 	"ozinshe_production/models"
 	"ozinshe_production/repositories"
 	"strconv"
@@ -41,25 +42,83 @@ type ResetPasswordRequest struct {
 	PasswordCheck 	string `json:"passwordCheck" binding:"required,min=8"`
 }
 
+// profileOwnerId определяет, чей профиль затрагивает запрос. Без :id (маршруты /profile/me) это
+// владелец токена. Чужой профиль доступен только при наличии права permission, каждая такая попытка логируется
+func profileOwnerId(c *gin.Context, permission string) (int, bool) {
+	logger := logger.GetLogger()
+
+	userId, exists := c.Get("userId")
+	if !exists {
+		logger.Error("Missing user ID in context")
+		c.JSON(http.StatusUnauthorized, models.NewApiError("Invalid user ID"))
+		return 0, false
+	}
+
+	currentId, ok := userId.(int)
+	if !ok {
+		logger.Error("Invalid user ID type", zap.Any("userId", userId))
+		c.JSON(http.StatusUnauthorized, models.NewApiError("Invalid user ID"))
+		return 0, false
+	}
+
+	idStr := c.Param("id")
+	if idStr == "" {
+		return currentId, true
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		logger.Error("Invalid user id", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid user id"))
+		return 0, false
+	}
+
+	if id == currentId {
+		return id, true
+	}
+
+	allowed, ok := middlewares.HasPermission(c, permission)
+	if !ok {
+		return 0, false
+	}
+
+	fields := []zap.Field{
+		zap.Int("userId", currentId),
+		zap.Int("targetUserId", id),
+		zap.String("method", c.Request.Method),
+		zap.String("route", c.FullPath()),
+		zap.String("permission", permission),
+	}
+
+	if !allowed {
+		logger.Warn("Cross-user profile access denied", fields...)
+		c.JSON(http.StatusForbidden, models.NewApiError("access to another user's profile is not allowed"))
+		return 0, false
+	}
+
+	logger.Info("Cross-user profile access granted", fields...)
+	return id, true
+}
+
 // UserProfile godoc
 // @Summary Get user profile
-// @Description Retrieves a user profile by ID
+// @Description Retrieves the profile of the token owner. Other users' profiles require the users:read permission
 // @Tags Public Profile
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
 // @Success 200 {object} profileResponse
 // @Failure 400 {object} models.ApiError "Invalid user id"
+// @Failure 401 {object} models.ApiError "Unauthorized"
+// @Failure 403 {object} models.ApiError "Access to another user's profile is not allowed"
 // @Failure 404 {object} models.ApiError "User not found"
-// @Router /public/profile/{id} [get]
+// @Router /profile/me [get]
+// @Router /profile/{id} [get]
 func (h *ProfilesHandler) UserProfile(c *gin.Context) {
 	logger := logger.GetLogger()
 
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		logger.Error("Invalid user id", zap.String("id", idStr))
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid user id"))
+	id, ok := profileOwnerId(c, models.PermUsersRead)
+	if !ok {
 		return
 	}
 
@@ -83,7 +142,7 @@ func (h *ProfilesHandler) UserProfile(c *gin.Context) {
 
 // Update godoc
 // @Summary Update user profile
-// @Description Updates the details of a user profile by ID
+// @Description Updates the profile of the token owner. Other users' profiles require the users:write permission
 // @Tags Public Profile
 // @Accept json
 // @Produce json
@@ -91,20 +150,20 @@ func (h *ProfilesHandler) UserProfile(c *gin.Context) {
 // @Param body body updateRequest true "Update Profile Data"
 // @Success 200 {string} string "Profile updated successfully"
 // @Failure 400 {object} models.ApiError "Invalid input data"
+// @Failure 401 {object} models.ApiError "Unauthorized"
+// @Failure 403 {object} models.ApiError "Access to another user's profile is not allowed"
 // @Failure 404 {object} models.ApiError "User not found"
-// @Router /public/profile/{id} [put]
+// @Router /profile/me [put]
+// @Router /profile/{id} [put]
 func (h *ProfilesHandler) Update(c *gin.Context) {
 	logger := logger.GetLogger()
 
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		logger.Error("Invalid user id", zap.String("id", idStr))
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid user id"))
+	id, ok := profileOwnerId(c, models.PermUsersWrite)
+	if !ok {
 		return
 	}
 
-	_, err = h.userRepo.FindById(c, id)
+	_, err := h.userRepo.FindById(c, id)
 	if err != nil {
 		logger.Error("Failed to find user for update", zap.Int("id", id), zap.Error(err))
 		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
@@ -148,7 +207,7 @@ func (h *ProfilesHandler) Update(c *gin.Context) {
 
 // ChangePassword godoc
 // @Summary Change user password
-// @Description Allows a user to change their password
+// @Description Changes the password of the token owner. Other users' passwords require the users:write permission
 // @Tags Public Profile
 // @Accept json
 // @Produce json
@@ -157,22 +216,15 @@ func (h *ProfilesHandler) Update(c *gin.Context) {
 // @Success 200 {string} string "Password changed successfully"
 // @Failure 400 {object} models.ApiError "Invalid input data"
 // @Failure 401 {object} models.ApiError "Unauthorized"
+// @Failure 403 {object} models.ApiError "Access to another user's profile is not allowed"
 // @Failure 500 {object} models.ApiError "Internal server error"
-// @Router /public/profile/changepassword/{id} [put]
+// @Router /profile/me/password [put]
+// @Router /profile/changepassword/{id} [put]
 func (h *ProfilesHandler) ChangePassword(c *gin.Context) {
 	logger := logger.GetLogger()
 
-	userId, exists := c.Get("userId")
-	if !exists {
-		logger.Error("Missing user ID in context")
-		c.JSON(http.StatusUnauthorized, models.NewApiError("Invalid user ID"))
-		return
-	}
-
-	id, ok := userId.(int)
+	id, ok := profileOwnerId(c, models.PermUsersWrite)
 	if !ok {
-		logger.Error("Invalid user ID type", zap.Any("userId", userId))
-		c.JSON(http.StatusUnauthorized, models.NewApiError("Invalid user ID"))
 		return
 	}
 
@@ -0,0 +1,440 @@
+package public
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"ozinshe_production/logger"
+	"ozinshe_production/models"
+	"ozinshe_production/repositories"
+	"ozinshe_production/storage"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultContinueWatchingLimit = 20
+	maxContinueWatchingLimit     = 50
+)
+
+type ProgressHandler struct {
+	playbackRepo *repositories.PlaybackRepository
+	moviesRepo   *repositories.MoviesRepository
+	seasonsRepo  *repositories.SeasonsRepository
+	episodesRepo *repositories.EpisodesRepository
+	storage      storage.Storage
+}
+
+func NewProgressHandler(
+	playbackRepo *repositories.PlaybackRepository,
+	moviesRepo *repositories.MoviesRepository,
+	seasonsRepo *repositories.SeasonsRepository,
+	episodesRepo *repositories.EpisodesRepository,
+	store storage.Storage) *ProgressHandler {
+	return &ProgressHandler{
+		playbackRepo: playbackRepo,
+		moviesRepo:   moviesRepo,
+		seasonsRepo:  seasonsRepo,
+		episodesRepo: episodesRepo,
+		storage:      store,
+	}
+}
+
+// ContinueWatchingItem — фильм, который пользователь начал смотреть. Для сериала Episode —
+// эпизод, с которого продолжить: недосмотренный или следующий за просмотренным
+type ContinueWatchingItem struct {
+	Movie           models.MovieCard    `json:"movie"`
+	Episode         *models.NextEpisode `json:"episode"`
+	PositionSeconds int                 `json:"position_seconds"`
+	DurationSeconds int                 `json:"duration_seconds"`
+}
+
+// ReportMovieProgress godoc
+// @Summary      Report movie playback position
+// @Description  Saves the playback position of a single film. The film is marked watched once the position
+// @Description  passes 90% of the duration, or explicitly with "watched". Series report progress per episode
+// @Tags         progress
+// @Accept       json
+// @Produce      json
+// @Param        movie_id path int true "Movie ID"
+// @Param        request body models.PlaybackReport true "Playback position"
+// @Success      200 {object} models.MovieWatchState "Updated watch state"
+// @Failure      400 {object} models.ApiError "Invalid data or the movie is a series"
+// @Failure      404 {object} models.ApiError "Movie not found"
+// @Failure      500 {object} models.ApiError "Failed to save progress"
+// @Security     Bearer
+// @Router       /progress/movies/{movie_id} [put]
+func (h *ProgressHandler) ReportMovieProgress(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	userId := c.GetInt("userId")
+	movieId, err := strconv.Atoi(c.Param("movie_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid movie ID"))
+		return
+	}
+
+	report, ok := bindPlaybackReport(c)
+	if !ok {
+		return
+	}
+
+	err = h.playbackRepo.SaveMovieProgress(c, userId, movieId, report)
+	switch {
+	case errors.Is(err, repositories.ErrMovieNotFound):
+		c.JSON(http.StatusNotFound, models.NewApiError("Movie not found"))
+		return
+	case errors.Is(err, repositories.ErrProgressRequiresEpisode):
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return
+	case err != nil:
+		logger.Error("Failed to save movie progress", zap.Int("user_id", userId), zap.Int("movie_id", movieId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to save progress"))
+		return
+	}
+
+	h.respondWatchState(c, userId, movieId)
+}
+
+// ReportEpisodeProgress godoc
+// @Summary      Report episode playback position
+// @Description  Saves the playback position of a series episode. The episode is marked watched once the position
+// @Description  passes 90% of the duration, or explicitly with "watched"
+// @Tags         progress
+// @Accept       json
+// @Produce      json
+// @Param        episode_id path int true "Episode ID"
+// @Param        request body models.PlaybackReport true "Playback position"
+// @Success      200 {object} models.MovieWatchState "Updated watch state of the series"
+// @Failure      400 {object} models.ApiError "Invalid data"
+// @Failure      404 {object} models.ApiError "Episode not found"
+// @Failure      500 {object} models.ApiError "Failed to save progress"
+// @Security     Bearer
+// @Router       /progress/episodes/{episode_id} [put]
+func (h *ProgressHandler) ReportEpisodeProgress(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	userId := c.GetInt("userId")
+	episodeId, err := strconv.Atoi(c.Param("episode_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid episode ID"))
+		return
+	}
+
+	report, ok := bindPlaybackReport(c)
+	if !ok {
+		return
+	}
+
+	movieId, err := h.playbackRepo.SaveEpisodeProgress(c, userId, episodeId, report)
+	if errors.Is(err, repositories.ErrEpisodeNotFound) {
+		c.JSON(http.StatusNotFound, models.NewApiError("Episode not found"))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to save episode progress", zap.Int("user_id", userId), zap.Int("episode_id", episodeId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to save progress"))
+		return
+	}
+
+	h.respondWatchState(c, userId, movieId)
+}
+
+// GetMovieProgress godoc
+// @Summary      Get watch state of a movie
+// @Description  Returns watched flags and playback positions of the movie and, for series, of every episode in order
+// @Tags         progress
+// @Produce      json
+// @Param        movie_id path int true "Movie ID"
+// @Success      200 {object} models.MovieWatchState "Watch state"
+// @Failure      400 {object} models.ApiError "Invalid movie ID"
+// @Failure      404 {object} models.ApiError "Movie not found"
+// @Failure      500 {object} models.ApiError "Failed to load progress"
+// @Security     Bearer
+// @Router       /progress/movies/{movie_id} [get]
+func (h *ProgressHandler) GetMovieProgress(c *gin.Context) {
+	movieId, ok := h.publishedMovieId(c)
+	if !ok {
+		return
+	}
+
+	h.respondWatchState(c, c.GetInt("userId"), movieId)
+}
+
+// ResetMovieProgress godoc
+// @Summary      Mark a movie as unwatched
+// @Description  Removes playback positions and watched flags of the movie and all of its episodes
+// @Tags         progress
+// @Produce      json
+// @Param        movie_id path int true "Movie ID"
+// @Success      200 {object} models.MovieWatchState "Cleared watch state"
+// @Failure      400 {object} models.ApiError "Invalid movie ID"
+// @Failure      500 {object} models.ApiError "Failed to reset progress"
+// @Security     Bearer
+// @Router       /progress/movies/{movie_id} [delete]
+func (h *ProgressHandler) ResetMovieProgress(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	userId := c.GetInt("userId")
+	movieId, err := strconv.Atoi(c.Param("movie_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid movie ID"))
+		return
+	}
+
+	err = h.playbackRepo.ResetMovieProgress(c, userId, movieId)
+	if err != nil {
+		logger.Error("Failed to reset movie progress", zap.Int("user_id", userId), zap.Int("movie_id", movieId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to reset progress"))
+		return
+	}
+
+	h.respondWatchState(c, userId, movieId)
+}
+
+// GetNextEpisode godoc
+// @Summary      Get the episode to play next
+// @Description  Resolves the episode to continue a series with by season and episode order: the first episode
+// @Description  when nothing was watched, the unfinished episode with its position, or the one after the last watched
+// @Tags         progress
+// @Produce      json
+// @Param        movie_id path int true "Movie ID"
+// @Success      200 {object} models.NextEpisode "Episode to play"
+// @Failure      400 {object} models.ApiError "Invalid movie ID"
+// @Failure      404 {object} models.ApiError "Movie not found, not a series or already watched to the end"
+// @Failure      500 {object} models.ApiError "Failed to resolve next episode"
+// @Security     Bearer
+// @Router       /progress/movies/{movie_id}/next [get]
+func (h *ProgressHandler) GetNextEpisode(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	movieId, ok := h.publishedMovieId(c)
+	if !ok {
+		return
+	}
+
+	userId := c.GetInt("userId")
+	progress, err := h.playbackRepo.FindMovieProgress(c, userId, movieId)
+	if err != nil {
+		logger.Error("Failed to load movie progress", zap.Int("user_id", userId), zap.Int("movie_id", movieId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to resolve next episode"))
+		return
+	}
+
+	var latest *models.PlaybackProgress
+	if len(progress) > 0 {
+		latest = &progress[0]
+	}
+
+	next, err := h.nextEpisode(c, movieId, latest)
+	if err != nil {
+		logger.Error("Failed to resolve next episode", zap.Int("movie_id", movieId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to resolve next episode"))
+		return
+	}
+	if next == nil {
+		c.JSON(http.StatusNotFound, models.NewApiError("No episode to play next"))
+		return
+	}
+
+	c.JSON(http.StatusOK, next)
+}
+
+// GetContinueWatching godoc
+// @Summary      Continue watching
+// @Description  Started and unfinished movies, most recently watched first. Series point at the episode to play next
+// @Tags         progress
+// @Produce      json
+// @Param        limit query int false "Maximum number of movies, 1-50 (default 20)"
+// @Success      200 {array} ContinueWatchingItem "Movies to continue"
+// @Failure      400 {object} models.ApiError "Invalid limit"
+// @Failure      500 {object} models.ApiError "Failed to load continue watching"
+// @Security     Bearer
+// @Router       /progress/continue-watching [get]
+func (h *ProgressHandler) GetContinueWatching(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	limit := defaultContinueWatchingLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxContinueWatchingLimit {
+			c.JSON(http.StatusBadRequest, models.NewApiError(fmt.Sprintf("limit must be between 1 and %d", maxContinueWatchingLimit)))
+			return
+		}
+	}
+
+	userId := c.GetInt("userId")
+	entries, err := h.playbackRepo.ContinueWatching(c, userId, limit)
+	if err != nil {
+		logger.Error("Failed to load continue watching", zap.Int("user_id", userId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load continue watching"))
+		return
+	}
+
+	cards := make([]models.MovieCard, len(entries))
+	for i, entry := range entries {
+		cards[i] = entry.Movie
+	}
+	if err := storage.ResolveCovers(c, h.storage, cards); err != nil {
+		logger.Error("Failed to build media URLs", zap.Int("user_id", userId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load continue watching"))
+		return
+	}
+
+	// Эпизоды всех сериалов из списка загружаются вместе, а не по фильму
+	var seriesIds []int
+	for _, entry := range entries {
+		if entry.Progress.EpisodeId != nil {
+			seriesIds = append(seriesIds, entry.Movie.Id)
+		}
+	}
+	episodeOrders, err := h.episodeOrders(c, seriesIds)
+	if err != nil {
+		logger.Error("Failed to load episode order", zap.Int("user_id", userId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load continue watching"))
+		return
+	}
+
+	items := make([]ContinueWatchingItem, 0, len(entries))
+	for i, entry := range entries {
+		item := ContinueWatchingItem{
+			Movie:           cards[i],
+			PositionSeconds: entry.Progress.PositionSeconds,
+			DurationSeconds: entry.Progress.DurationSeconds,
+		}
+
+		if entry.Progress.EpisodeId != nil {
+			next := models.ResolveNextEpisode(episodeOrders[entry.Movie.Id], &entry.Progress)
+			if next == nil {
+				continue
+			}
+			item.Episode = next
+			item.PositionSeconds = next.PositionSeconds
+			item.DurationSeconds = next.DurationSeconds
+		}
+
+		items = append(items, item)
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func bindPlaybackReport(c *gin.Context) (models.PlaybackReport, bool) {
+	var report models.PlaybackReport
+	if err := c.ShouldBindJSON(&report); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid data"))
+		return report, false
+	}
+	if err := report.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		return report, false
+	}
+	return report, true
+}
+
+// publishedMovieId разбирает :movie_id и отвечает 404, если фильм не опубликован
+func (h *ProgressHandler) publishedMovieId(c *gin.Context) (int, bool) {
+	movieId, err := strconv.Atoi(c.Param("movie_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid movie ID"))
+		return 0, false
+	}
+
+	published, err := h.moviesRepo.IsPublished(c, movieId)
+	if err != nil {
+		logger.GetLogger().Error("Failed to check movie", zap.Int("movie_id", movieId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load movie"))
+		return 0, false
+	}
+	if !published {
+		c.JSON(http.StatusNotFound, models.NewApiError("Movie not found"))
+		return 0, false
+	}
+	return movieId, true
+}
+
+func (h *ProgressHandler) respondWatchState(c *gin.Context, userId, movieId int) {
+	logger := logger.GetLogger()
+
+	state, err := h.watchState(c, userId, movieId)
+	if err != nil {
+		logger.Error("Failed to load watch state", zap.Int("user_id", userId), zap.Int("movie_id", movieId), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load progress"))
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
+}
+
+func (h *ProgressHandler) watchState(c context.Context, userId, movieId int) (models.MovieWatchState, error) {
+	episodes, err := h.episodeOrder(c, movieId)
+	if err != nil {
+		return models.MovieWatchState{}, err
+	}
+
+	progress, err := h.playbackRepo.FindMovieProgress(c, userId, movieId)
+	if err != nil {
+		return models.MovieWatchState{}, err
+	}
+
+	return models.NewMovieWatchState(movieId, episodes, progress), nil
+}
+
+func (h *ProgressHandler) nextEpisode(c context.Context, movieId int, latest *models.PlaybackProgress) (*models.NextEpisode, error) {
+	episodes, err := h.episodeOrder(c, movieId)
+	if err != nil {
+		return nil, err
+	}
+	return models.ResolveNextEpisode(episodes, latest), nil
+}
+
+// episodeOrder возвращает эпизоды сериала по порядку сезонов и серий. У полнометражного фильма список пуст
+func (h *ProgressHandler) episodeOrder(c context.Context, movieId int) ([]models.EpisodeRef, error) {
+	orders, err := h.episodeOrders(c, []int{movieId})
+	if err != nil {
+		return nil, err
+	}
+	return orders[movieId], nil
+}
+
+// episodeOrders возвращает эпизоды каждого из сериалов по порядку сезонов и серий за два запроса
+func (h *ProgressHandler) episodeOrders(c context.Context, movieIds []int) (map[int][]models.EpisodeRef, error) {
+	orders := make(map[int][]models.EpisodeRef, len(movieIds))
+	if len(movieIds) == 0 {
+		return orders, nil
+	}
+
+	seasons, err := h.seasonsRepo.FindAllByMovieIDs(c, movieIds)
+	if err != nil {
+		return nil, err
+	}
+	seasonIds := make([]int, 0, len(seasons))
+	for _, season := range seasons {
+		seasonIds = append(seasonIds, season.Id)
+	}
+
+	episodes, err := h.episodesRepo.FindAllBySeasonIDs(c, seasonIds)
+	if err != nil {
+		return nil, err
+	}
+	bySeason := make(map[int][]models.Episode, len(seasons))
+	for _, episode := range episodes {
+		bySeason[episode.SeasonID] = append(bySeason[episode.SeasonID], episode)
+	}
+
+	for _, season := range seasons {
+		for _, episode := range bySeason[season.Id] {
+			orders[season.MovieID] = append(orders[season.MovieID], models.EpisodeRef{
+				EpisodeId:     episode.Id,
+				EpisodeNumber: episode.Number,
+				SeasonId:      season.Id,
+				SeasonNumber:  season.Number,
+			})
+		}
+	}
+	return orders, nil
+}
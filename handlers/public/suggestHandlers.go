@@ -0,0 +1,91 @@
+package public
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"ozinshe_production/logger"
+	"ozinshe_production/models"
+	"ozinshe_production/repositories"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSuggestLimit = 5
+	maxSuggestLimit     = 20
+	maxSuggestPrefix    = 100
+
+	// Подсказки нужны на каждое нажатие клавиши: медленный ответ клиенту уже не нужен
+	suggestTimeout = 300 * time.Millisecond
+)
+
+type SuggestHandler struct {
+	suggestionsRepo *repositories.SuggestionsRepository
+}
+
+func NewSuggestHandler(suggestionsRepo *repositories.SuggestionsRepository) *SuggestHandler {
+	return &SuggestHandler{suggestionsRepo: suggestionsRepo}
+}
+
+// Suggest godoc
+// @Summary      Search suggestions
+// @Description  Returns typeahead completions for a prefix: movie titles, keywords, people (directors and producers),
+// @Description  categories and genres. Up to limit suggestions of each type, most popular first
+// @Tags         search
+// @Accept       json
+// @Produce      json
+// @Param        query query string true "Prefix typed by the user"
+// @Param        limit query int false "Suggestions per type, 1-20 (default 5)"
+// @Success      200 {object} gin.H "Suggestions"
+// @Failure      400 {object} models.ApiError "Bad request: empty query or invalid limit"
+// @Failure      500 {object} models.ApiError "Server error: failed to load suggestions"
+// @Router       /search/suggest [get]
+func (h *SuggestHandler) Suggest(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	prefix := strings.TrimSpace(c.Query("query"))
+	if prefix == "" {
+		c.JSON(http.StatusBadRequest, models.NewApiError("Empty search query"))
+		return
+	}
+	if utf8.RuneCountInString(prefix) > maxSuggestPrefix {
+		c.JSON(http.StatusBadRequest, models.NewApiError(fmt.Sprintf("query must be at most %d characters", maxSuggestPrefix)))
+		return
+	}
+
+	limit := defaultSuggestLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxSuggestLimit {
+			c.JSON(http.StatusBadRequest, models.NewApiError(fmt.Sprintf("limit must be between 1 and %d", maxSuggestLimit)))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c, suggestTimeout)
+	defer cancel()
+
+	suggestions, err := h.suggestionsRepo.Suggest(ctx, prefix, limit)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		// Пустой список лучше ошибки: следующее нажатие клавиши всё равно запросит подсказки заново
+		logger.Warn("Search suggestions timed out", zap.String("query", prefix), zap.Duration("timeout", suggestTimeout))
+		c.JSON(http.StatusOK, gin.H{"suggestions": []models.Suggestion{}})
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to load search suggestions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to load suggestions"))
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=60")
+	c.JSON(http.StatusOK, gin.H{"suggestions": suggestions})
+}
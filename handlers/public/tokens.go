@@ -0,0 +1,73 @@
+package public
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"ozinshe_production/config"
+	"ozinshe_production/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type tokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// issueTokens выдаёт пару access/refresh токенов и начинает новое семейство refresh-токенов
+func (h *AuthHandlers) issueTokens(c *gin.Context, userId int) (tokenResponse, error) {
+	accessToken, err := generateJWT(userId)
+	if err != nil {
+		return tokenResponse{}, err
+	}
+
+	refreshToken, refreshTokenHash, err := generateRefreshToken()
+	if err != nil {
+		return tokenResponse{}, err
+	}
+
+	err = h.refreshTokensRepo.Create(c, models.RefreshToken{
+		UserId:    userId,
+		FamilyId:  uuid.NewString(),
+		TokenHash: refreshTokenHash,
+		ExpiresAt: time.Now().Add(config.Config.JwtRefreshExpiresIn),
+	})
+	if err != nil {
+		return tokenResponse{}, err
+	}
+
+	return tokenResponse{Token: accessToken, RefreshToken: refreshToken}, nil
+}
+
+func generateJWT(userId int) (string, error) {
+	claims := jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Subject:   strconv.Itoa(userId),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.Config.JwtExpiresIn)),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(config.Config.JwtSecretKey))
+}
+
+// generateRefreshToken возвращает непрозрачный токен для клиента и его хэш для базы
+func generateRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,10 +1,12 @@
 package public
 
 import (
+	"errors"
 	"net/http"
 	"ozinshe_production/logger"
 	"ozinshe_production/models"
 	"ozinshe_production/repositories"
+	"ozinshe_production/storage"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -13,12 +15,15 @@ import (
 
 type WatchlistHandler struct {
 	watchlistRepo *repositories.WatchlistRepository
+	storage       storage.Storage
 }
 
 func NewWatchlistHandler(
-	watchlistRepo *repositories.WatchlistRepository) *WatchlistHandler {
+	watchlistRepo *repositories.WatchlistRepository,
+	store storage.Storage) *WatchlistHandler {
 	return &WatchlistHandler{
 		watchlistRepo: watchlistRepo,
+		storage:       store,
 	}
 }
 
@@ -37,6 +42,7 @@ type watchlistResponse struct {
 // @Param        movie_id path int true "Movie ID to add"
 // @Success      200 {object} watchlistResponse "Movie successfully added to the watchlist"
 // @Failure      400 {object} models.ApiError "Invalid movie ID"
+// @Failure      404 {object} models.ApiError "Movie not found"
 // @Failure      500 {object} models.ApiError "Failed to add to watchlist"
 // @Router       /watchlist/{movie_id} [post]
 func (h *WatchlistHandler) AddToWatchlist(c *gin.Context) {
@@ -51,6 +57,10 @@ func (h *WatchlistHandler) AddToWatchlist(c *gin.Context) {
 	}
 
 	err = h.watchlistRepo.AddToWatchlist(c, userID, movieID)
+	if errors.Is(err, repositories.ErrMovieNotFound) {
+		c.JSON(http.StatusNotFound, models.NewApiError("Movie not found"))
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to add movie to watchlist", zap.Int("user_id", userID), zap.Int("movie_id", movieID), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to add to watchlist"))
@@ -81,6 +91,14 @@ func (h *WatchlistHandler) GetWatchlist(c *gin.Context) {
 		return
 	}
 
+	for i := range movies {
+		if err := storage.ResolveMovieMedia(c, h.storage, &movies[i].Media); err != nil {
+			logger.Error("Failed to build media URLs", zap.Int("user_id", userID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to get watchlist"))
+			return
+		}
+	}
+
 	logger.Info("Watchlist retrieved", zap.Int("user_id", userID), zap.Int("movies_count", len(movies)))
 	c.JSON(http.StatusOK, movies)
 }
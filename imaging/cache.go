@@ -0,0 +1,204 @@
+package imaging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"ozinshe_production/storage"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Время последнего обращения к файлу кэша обновляется не чаще этого, чтобы чтение не превращалось в запись
+const touchInterval = time.Hour
+
+// Cache — хранилище с дисковым кэшем для раздачи медиафайлов: хранит копии оригиналов из удалённого
+// хранилища и уменьшенные варианты. Ключи загрузок не переиспользуются, поэтому копия по ключу
+// никогда не устаревает. Delete убирает и закэшированные копии, поэтому удалённый файл перестаёт
+// раздаваться. Размер кэша ограничивает Trim: он удаляет давно не запрошенные файлы
+type Cache struct {
+	storage.Storage
+	dir string
+}
+
+func NewCache(dir string, store storage.Storage) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Cache{Storage: store, dir: dir}, nil
+}
+
+// Original открывает оригинал. Файл локального хранилища отдаётся как есть, объект из удалённого
+// хранилища скачивается в кэш при первом запросе
+func (c *Cache) Original(ctx context.Context, key string) (*os.File, error) {
+	if err := storage.ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	path := c.path("originals", key)
+	if file, err := openCached(path); err == nil {
+		return file, nil
+	}
+
+	body, err := c.Storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if file, ok := body.(*os.File); ok {
+		return file, nil
+	}
+	defer body.Close()
+
+	if err := writeFile(path, func(w io.Writer) error {
+		_, err := io.Copy(w, body)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Variant открывает вариант изображения, при первом запросе создавая его из оригинала
+func (c *Cache) Variant(ctx context.Context, key string, v Variant) (*os.File, error) {
+	original, err := c.Original(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer original.Close()
+
+	info, err := Inspect(original)
+	if err != nil {
+		return nil, err
+	}
+	v = v.resolve(info)
+
+	path := filepath.Join(c.path("variants", key), v.fileName())
+	if file, err := openCached(path); err == nil {
+		return file, nil
+	}
+
+	if _, err := original.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := writeFile(path, func(w io.Writer) error {
+		return Resize(original, info, v, w)
+	}); err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Delete удаляет объект из хранилища и его копии из кэша
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	if err := c.Storage.Delete(ctx, key); err != nil {
+		return err
+	}
+	if err := os.Remove(c.path("originals", key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.RemoveAll(c.path("variants", key))
+}
+
+// TrimReport — итог Trim: сколько файлов удалено и сколько места освобождено
+type TrimReport struct {
+	Removed    int
+	FreedBytes int64
+}
+
+// Trim удаляет из кэша файлы, к которым дольше всего не обращались, пока общий размер не станет
+// не больше maxBytes. Удалённые копии при следующем запросе скачиваются и создаются заново
+func (c *Cache) Trim(ctx context.Context, maxBytes int64) (TrimReport, error) {
+	var report TrimReport
+
+	type cachedFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []cachedFile
+	var total int64
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Временные файлы ещё дописываются параллельными запросами
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".cache-") {
+			return nil
+		}
+		info, err := d.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		files = append(files, cachedFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	slices.SortFunc(files, func(a, b cachedFile) int { return a.modTime.Compare(b.modTime) })
+	for _, file := range files {
+		if total <= maxBytes {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		if err := os.Remove(file.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return report, err
+		}
+		total -= file.size
+		report.Removed++
+		report.FreedBytes += file.size
+	}
+	return report, nil
+}
+
+func (c *Cache) path(kind, key string) string {
+	return filepath.Join(c.dir, kind, filepath.FromSlash(key))
+}
+
+// openCached открывает файл кэша и отмечает обращение к нему временем изменения, по которому Trim
+// выбирает, что удалить
+func openCached(path string) (*os.File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := file.Stat(); err == nil && time.Since(info.ModTime()) > touchInterval {
+		now := time.Now()
+		os.Chtimes(path, now, now)
+	}
+	return file, nil
+}
+
+// writeFile пишет файл через временный и переименовывает, чтобы параллельный запрос не прочитал его
+// наполовину. Два запроса одного варианта просто создадут его дважды
+func writeFile(path string, write func(io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".cache-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
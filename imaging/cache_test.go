@@ -0,0 +1,240 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"ozinshe_production/storage"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// memoryStorage отдаёт объекты не файлами, как удалённое хранилище
+type memoryStorage struct {
+	objects map[string][]byte
+	gets    int
+}
+
+func (m *memoryStorage) Put(c context.Context, key string, body io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(body)
+	m.objects[key] = data
+	return err
+}
+
+func (m *memoryStorage) Get(c context.Context, key string) (io.ReadCloser, error) {
+	m.gets++
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memoryStorage) Delete(c context.Context, key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memoryStorage) List(c context.Context, fn func(storage.Object) error) error {
+	for key, data := range m.objects {
+		if err := fn(storage.Object{Key: key, Size: int64(len(data))}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryStorage) URL(c context.Context, key string) (string, error) {
+	return "/media/" + key, nil
+}
+
+func TestParseVariant(t *testing.T) {
+	for _, tc := range []struct {
+		width, format string
+		want          Variant
+		ok            bool
+	}{
+		{"", "", Variant{}, true},
+		{"320", "webp", Variant{320, FormatWebP}, true},
+		{"", "jpg", Variant{0, FormatJPEG}, true},
+		{"321", "", Variant{}, false},
+		{"-1", "", Variant{}, false},
+		{"abc", "", Variant{}, false},
+		{"", "gif", Variant{}, false},
+	} {
+		got, err := ParseVariant(tc.width, tc.format)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Errorf("ParseVariant(%q, %q) = %+v, %v", tc.width, tc.format, got, err)
+		}
+	}
+}
+
+func TestResizeKeepsAspectRatio(t *testing.T) {
+	data := encodedImage(t, FormatPNG, 1280, 720)
+	info, err := Inspect(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []string{FormatJPEG, FormatPNG, FormatWebP} {
+		var out bytes.Buffer
+		v := Variant{Width: 320, Format: format}.resolve(info)
+		if err := Resize(bytes.NewReader(data), info, v, &out); err != nil {
+			t.Fatalf("Resize to %s: %v", format, err)
+		}
+
+		got, err := Inspect(&out)
+		if err != nil {
+			t.Fatalf("Inspect %s: %v", format, err)
+		}
+		if want := (Info{format, 320, 180}); got != want {
+			t.Errorf("resized = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestCacheCreatesVariantOnce(t *testing.T) {
+	store := &memoryStorage{objects: map[string][]byte{"cover.png": encodedImage(t, FormatPNG, 600, 900)}}
+	dir := t.TempDir()
+	cache, err := NewCache(dir, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	first, err := cache.Variant(ctx, "cover.png", Variant{Width: 320, Format: FormatWebP})
+	if err != nil {
+		t.Fatalf("Variant: %v", err)
+	}
+	first.Close()
+	if filepath.Base(first.Name()) != "w320.webp" {
+		t.Errorf("variant file = %s", first.Name())
+	}
+
+	second, err := cache.Variant(ctx, "cover.png", Variant{Width: 320, Format: FormatWebP})
+	if err != nil {
+		t.Fatalf("second Variant: %v", err)
+	}
+	second.Close()
+	if second.Name() != first.Name() || store.gets != 1 {
+		t.Errorf("variant recreated: %s, %s, %d gets", first.Name(), second.Name(), store.gets)
+	}
+
+	// Ширина больше исходной не увеличивает изображение
+	original, err := cache.Variant(ctx, "cover.png", Variant{Width: 1920})
+	if err != nil {
+		t.Fatalf("Variant wider than original: %v", err)
+	}
+	original.Close()
+	if filepath.Base(original.Name()) != "w600.png" {
+		t.Errorf("variant file = %s, want w600.png", original.Name())
+	}
+
+	if err := cache.Delete(ctx, "cover.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := os.Stat(first.Name()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("variant left after delete: %v", err)
+	}
+	if _, err := cache.Original(ctx, "cover.png"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Original after delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCacheServesLocalFilesInPlace(t *testing.T) {
+	local, err := storage.NewLocal(t.TempDir(), "/media")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, err := NewCache(t.TempDir(), local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	data := encodedImage(t, FormatJPEG, 640, 360)
+	if err := local.Put(ctx, "shot.jpg", bytes.NewReader(data), int64(len(data)), "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := cache.Original(ctx, "shot.jpg")
+	if err != nil {
+		t.Fatalf("Original: %v", err)
+	}
+	defer file.Close()
+	if _, err := os.Stat(cache.path("originals", "shot.jpg")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("local file copied into cache: %v", err)
+	}
+
+	for _, key := range []string{"../shot.jpg", "", "a/../../b"} {
+		if _, err := cache.Original(ctx, key); !errors.Is(err, storage.ErrInvalidKey) {
+			t.Errorf("Original(%q) = %v, want ErrInvalidKey", key, err)
+		}
+	}
+
+	if _, err := cache.Variant(ctx, "missing.jpg", Variant{Width: 320}); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Variant of a missing file = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCacheTrimRemovesLeastRecentlyUsed(t *testing.T) {
+	store := &memoryStorage{objects: map[string][]byte{
+		"old.bin":  bytes.Repeat([]byte{1}, 100),
+		"used.bin": bytes.Repeat([]byte{2}, 100),
+		"new.bin":  bytes.Repeat([]byte{3}, 100),
+	}}
+	cache, err := NewCache(t.TempDir(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// old и used скачаны давно, но к used только что обращались
+	for i, key := range []string{"old.bin", "used.bin", "new.bin"} {
+		file, err := cache.Original(ctx, key)
+		if err != nil {
+			t.Fatalf("Original(%s): %v", key, err)
+		}
+		file.Close()
+		if key != "new.bin" {
+			past := time.Now().Add(-time.Duration(3-i) * 24 * time.Hour)
+			if err := os.Chtimes(cache.path("originals", key), past, past); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	file, err := cache.Original(ctx, "used.bin")
+	if err != nil {
+		t.Fatalf("Original(used.bin): %v", err)
+	}
+	file.Close()
+
+	report, err := cache.Trim(ctx, 250)
+	if err != nil {
+		t.Fatalf("Trim: %v", err)
+	}
+	if report.Removed != 1 || report.FreedBytes != 100 {
+		t.Errorf("Trim report = %+v, want 1 file and 100 bytes", report)
+	}
+	if _, err := os.Stat(cache.path("originals", "old.bin")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("least recently used file kept: %v", err)
+	}
+	for _, key := range []string{"used.bin", "new.bin"} {
+		if _, err := os.Stat(cache.path("originals", key)); err != nil {
+			t.Errorf("%s removed: %v", key, err)
+		}
+	}
+
+	// Удалённая копия скачивается заново
+	file, err = cache.Original(ctx, "old.bin")
+	if err != nil {
+		t.Fatalf("Original after Trim: %v", err)
+	}
+	file.Close()
+	if store.gets != 4 {
+		t.Errorf("gets = %d, want 4", store.gets)
+	}
+}
@@ -0,0 +1,89 @@
+package imaging
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/webp"
+)
+
+// Поддерживаемые форматы изображений
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+)
+
+// ErrUnsupportedFormat — содержимое файла не JPEG, PNG или WebP, независимо от расширения
+var ErrUnsupportedFormat = errors.New("unsupported image format, expected JPEG, PNG or WebP")
+
+// ErrInvalidImage — формат распознан, но заголовок изображения повреждён
+var ErrInvalidImage = errors.New("invalid image")
+
+// sniffLen — сколько байт смотрит http.DetectContentType
+const sniffLen = 512
+
+var formats = map[string]struct {
+	contentType  string
+	ext          string
+	decodeConfig func(io.Reader) (image.Config, error)
+}{
+	FormatJPEG: {"image/jpeg", ".jpg", jpeg.DecodeConfig},
+	FormatPNG:  {"image/png", ".png", png.DecodeConfig},
+	FormatWebP: {"image/webp", ".webp", webp.DecodeConfig},
+}
+
+// Info — формат и размеры изображения, определённые по содержимому
+type Info struct {
+	Format string
+	Width  int
+	Height int
+}
+
+func (i Info) ContentType() string {
+	return formats[i.Format].contentType
+}
+
+// Ext — расширение ключа в хранилище; расширение из имени загруженного файла не используется
+func (i Info) Ext() string {
+	return formats[i.Format].ext
+}
+
+// Inspect определяет формат по сигнатуре файла и читает размеры из заголовка, не декодируя пиксели
+func Inspect(r io.Reader) (Info, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return Info{}, err
+	}
+
+	var info Info
+	switch http.DetectContentType(head) {
+	case "image/jpeg":
+		info.Format = FormatJPEG
+	case "image/png":
+		info.Format = FormatPNG
+	case "image/webp":
+		info.Format = FormatWebP
+	default:
+		return Info{}, ErrUnsupportedFormat
+	}
+
+	config, err := formats[info.Format].decodeConfig(br)
+	if err != nil {
+		return Info{}, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return Info{}, fmt.Errorf("%w: empty image", ErrInvalidImage)
+	}
+
+	info.Width = config.Width
+	info.Height = config.Height
+	return info, nil
+}
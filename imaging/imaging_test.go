@@ -0,0 +1,93 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// lossless WebP 1x1
+const tinyWebP = "UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA=="
+
+func encodedImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatJPEG:
+		err = jpeg.Encode(&buf, img, nil)
+	case FormatPNG:
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		t.Fatalf("encode %s: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+func TestInspectDetectsFormatByContent(t *testing.T) {
+	webp, err := base64.StdEncoding.DecodeString(tinyWebP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name        string
+		data        []byte
+		want        Info
+		contentType string
+		ext         string
+	}{
+		{"jpeg", encodedImage(t, FormatJPEG, 640, 360), Info{FormatJPEG, 640, 360}, "image/jpeg", ".jpg"},
+		{"png", encodedImage(t, FormatPNG, 300, 450), Info{FormatPNG, 300, 450}, "image/png", ".png"},
+		{"webp", webp, Info{FormatWebP, 1, 1}, "image/webp", ".webp"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			info, err := Inspect(bytes.NewReader(tc.data))
+			if err != nil {
+				t.Fatalf("Inspect: %v", err)
+			}
+			if info != tc.want {
+				t.Errorf("Inspect = %+v, want %+v", info, tc.want)
+			}
+			if info.ContentType() != tc.contentType || info.Ext() != tc.ext {
+				t.Errorf("content type %q, ext %q", info.ContentType(), info.Ext())
+			}
+		})
+	}
+}
+
+func TestInspectRejectsOtherContent(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"html":  []byte("<!DOCTYPE html><html><body><script>alert(1)</script></body></html>"),
+		"gif":   []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;"),
+		"empty": nil,
+	} {
+		if _, err := Inspect(bytes.NewReader(data)); !errors.Is(err, ErrUnsupportedFormat) {
+			t.Errorf("%s: err = %v, want ErrUnsupportedFormat", name, err)
+		}
+	}
+}
+
+func TestInspectRejectsBrokenHeader(t *testing.T) {
+	data := encodedImage(t, FormatPNG, 10, 10)
+	// Сигнатура PNG на месте, заголовок IHDR обрезан
+	truncated := data[:12]
+	if _, err := Inspect(bytes.NewReader(truncated)); !errors.Is(err, ErrInvalidImage) {
+		t.Errorf("err = %v, want ErrInvalidImage", err)
+	}
+
+	// Формат определяется только по первым байтам: PNG после постороннего префикса не принимается
+	if _, err := Inspect(strings.NewReader("fake.png" + string(data))); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("err = %v, want ErrUnsupportedFormat", err)
+	}
+}
@@ -0,0 +1,114 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// VariantWidths — ширины, до которых можно уменьшать изображения. Список закрыт, чтобы перебором
+// ширин нельзя было забить дисковый кэш
+var VariantWidths = []int{160, 320, 480, 640, 960, 1280, 1920}
+
+// maxDecodePixels — изображения больше этого не декодируются, чтобы старый огромный файл не съел память
+const maxDecodePixels = 8000 * 6000
+
+// ErrImageTooLarge — изображение слишком большое, чтобы уменьшать его на лету
+var ErrImageTooLarge = errors.New("image is too large to resize")
+
+var decoders = map[string]func(io.Reader) (image.Image, error){
+	FormatJPEG: jpeg.Decode,
+	FormatPNG:  png.Decode,
+	FormatWebP: webp.Decode,
+}
+
+var encoders = map[string]func(io.Writer, image.Image) error{
+	FormatJPEG: func(w io.Writer, img image.Image) error { return jpeg.Encode(w, img, &jpeg.Options{Quality: 85}) },
+	FormatPNG:  png.Encode,
+	// Кодировщик без cgo, сжимает без потерь
+	FormatWebP: func(w io.Writer, img image.Image) error { return nativewebp.Encode(w, img, nil) },
+}
+
+// Variant — производная копия изображения: ширина (0 — исходная) и формат (пустой — исходный)
+type Variant struct {
+	Width  int
+	Format string
+}
+
+// ParseVariant разбирает параметры w и format запроса к медиафайлу
+func ParseVariant(width, format string) (Variant, error) {
+	var v Variant
+	if width != "" {
+		w, err := strconv.Atoi(width)
+		if err != nil || !slices.Contains(VariantWidths, w) {
+			return Variant{}, fmt.Errorf("w must be one of %v", VariantWidths)
+		}
+		v.Width = w
+	}
+
+	switch format {
+	case "":
+	case "jpg", FormatJPEG:
+		v.Format = FormatJPEG
+	case FormatPNG, FormatWebP:
+		v.Format = format
+	default:
+		return Variant{}, fmt.Errorf("format must be jpeg, png or webp")
+	}
+	return v, nil
+}
+
+func (v Variant) IsOriginal() bool {
+	return v.Width == 0 && v.Format == ""
+}
+
+// resolve подставляет исходные ширину и формат. Изображение не увеличивается: ширина больше
+// исходной даёт исходную
+func (v Variant) resolve(info Info) Variant {
+	if v.Width == 0 || v.Width > info.Width {
+		v.Width = info.Width
+	}
+	if v.Format == "" {
+		v.Format = info.Format
+	}
+	return v
+}
+
+// fileName — имя файла варианта в кэше
+func (v Variant) fileName() string {
+	return fmt.Sprintf("w%d%s", v.Width, formats[v.Format].ext)
+}
+
+// Resize уменьшает изображение до ширины варианта с сохранением пропорций и кодирует в формат варианта.
+// info — результат Inspect для того же изображения, v — уже с подставленными шириной и форматом
+func Resize(r io.Reader, info Info, v Variant, w io.Writer) error {
+	if info.Width*info.Height > maxDecodePixels {
+		return ErrImageTooLarge
+	}
+
+	src, err := decoders[info.Format](r)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	height := max(1, (info.Height*v.Width+info.Width/2)/info.Width)
+	dst := image.NewNRGBA(image.Rect(0, 0, v.Width, height))
+	op := draw.Src
+	if v.Format == FormatJPEG {
+		// В JPEG нет прозрачности: прозрачные области ложатся на белый фон, а не на чёрный
+		draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+		op = draw.Over
+	}
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), op, nil)
+
+	return encoders[v.Format](w, dst)
+}
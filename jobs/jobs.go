@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+	"ozinshe_production/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// runEvery запускает fn раз в interval, пока не отменён контекст. Задача с нулевым или отрицательным
+// интервалом не запускается: так её можно отключить в конфигурации
+func runEvery(c context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	logger := logger.GetLogger()
+
+	if interval <= 0 {
+		logger.Warn("Background job disabled: interval must be positive", zap.String("job", name), zap.Duration("interval", interval))
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.Done():
+				logger.Info("Background job stopped", zap.String("job", name))
+				return
+			case <-ticker.C:
+				if err := fn(c); err != nil {
+					logger.Error("Background job failed", zap.String("job", name), zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	logger.Info("Background job started", zap.String("job", name), zap.Duration("interval", interval))
+}
@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunEverySkipsNonPositiveInterval(t *testing.T) {
+	c, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		runEvery(c, "test", interval, func(context.Context) error {
+			t.Errorf("job with interval %s ran", interval)
+			return nil
+		})
+	}
+}
+
+func TestRunEveryRunsUntilCancelled(t *testing.T) {
+	c, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := make(chan struct{}, 1)
+	runEvery(c, "test", time.Millisecond, func(context.Context) error {
+		select {
+		case runs <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Fatal("job did not run")
+	}
+}
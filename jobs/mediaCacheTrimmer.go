@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"ozinshe_production/imaging"
+	"ozinshe_production/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StartMediaCacheTrimmer периодически удаляет из дискового кэша медиафайлов давно не запрошенные
+// копии, пока кэш не уложится в maxBytes
+func StartMediaCacheTrimmer(c context.Context, cache *imaging.Cache, interval time.Duration, maxBytes int64) {
+	runEvery(c, "media_cache_trimmer", interval, func(c context.Context) error {
+		report, err := cache.Trim(c, maxBytes)
+		if err != nil {
+			return err
+		}
+
+		if report.Removed > 0 {
+			logger.GetLogger().Info("Media cache trimmed",
+				zap.Int("removed", report.Removed),
+				zap.Int64("freed_bytes", report.FreedBytes))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"ozinshe_production/logger"
+	"ozinshe_production/repositories"
+	"ozinshe_production/storage"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StartMediaCollector периодически удаляет из хранилища файлы, на которые больше не ссылаются
+// фильмы, жанры и возрастные ограничения и которые старше grace
+func StartMediaCollector(c context.Context, mediaRepo *repositories.MediaRepository, store storage.Storage, interval, grace time.Duration) {
+	runEvery(c, "media_collector", interval, func(c context.Context) error {
+		logger := logger.GetLogger()
+
+		referenced, err := mediaRepo.ReferencedMediaKeys(c)
+		if err != nil {
+			return err
+		}
+
+		report, err := storage.CollectGarbage(c, store, referenced, grace, false)
+		if err != nil {
+			return err
+		}
+
+		if report.Deleted > 0 || report.Failed > 0 {
+			logger.Info("Orphaned media collected",
+				zap.Int("deleted", report.Deleted),
+				zap.Int64("freed_bytes", report.FreedBytes),
+				zap.Int("failed", report.Failed))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"ozinshe_production/logger"
+	"ozinshe_production/repositories"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StartMoviesPublisher периодически публикует запланированные фильмы и снимает с публикации
+// фильмы, у которых истёк срок показа
+func StartMoviesPublisher(c context.Context, moviesRepo *repositories.MoviesRepository, interval time.Duration) {
+	runEvery(c, "movies_publisher", interval, func(c context.Context) error {
+		logger := logger.GetLogger()
+
+		published, archived, err := moviesRepo.ApplySchedule(c)
+		if err != nil {
+			return err
+		}
+
+		if published > 0 || archived > 0 {
+			logger.Info("Movie schedule applied", zap.Int64("published", published), zap.Int64("archived", archived))
+		}
+		return nil
+	})
+}
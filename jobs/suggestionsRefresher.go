@@ -0,0 +1,13 @@
+package jobs
+
+import (
+	"context"
+	"ozinshe_production/repositories"
+	"time"
+)
+
+// StartSuggestionsRefresher периодически перестраивает индекс подсказок поиска,
+// чтобы в автодополнении появлялись новые фильмы, категории и жанры
+func StartSuggestionsRefresher(c context.Context, suggestionsRepo *repositories.SuggestionsRepository, interval time.Duration) {
+	runEvery(c, "suggestions_refresher", interval, suggestionsRepo.Refresh)
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"ozinshe_production/logger"
+	"ozinshe_production/repositories"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StartTokensSweeper периодически удаляет отозванные и refresh-токены с истёкшим сроком действия
+func StartTokensSweeper(c context.Context,
+	revokedTokensRepo *repositories.RevokedTokensRepository,
+	refreshTokensRepo *repositories.RefreshTokensRepository,
+	interval time.Duration) {
+	runEvery(c, "tokens_sweeper", interval, func(c context.Context) error {
+		logger := logger.GetLogger()
+
+		revoked, err := revokedTokensRepo.DeleteExpired(c)
+		if err != nil {
+			return err
+		}
+
+		refresh, err := refreshTokensRepo.DeleteExpired(c)
+		if err != nil {
+			return err
+		}
+
+		if revoked > 0 || refresh > 0 {
+			logger.Info("Expired tokens purged", zap.Int64("revoked_tokens", revoked), zap.Int64("refresh_tokens", refresh))
+		}
+		return nil
+	})
+}
@@ -2,13 +2,19 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"ozinshe_production/config"
 	"ozinshe_production/docs"
 	"ozinshe_production/handlers/admin"
 	"ozinshe_production/handlers/public"
+	"ozinshe_production/imaging"
+	"ozinshe_production/jobs"
 	"ozinshe_production/logger"
 	"ozinshe_production/middlewares"
+	"ozinshe_production/migrations"
 	"ozinshe_production/repositories"
+	"ozinshe_production/storage"
+	"os"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -81,6 +87,38 @@ func main() {
 		logger.Fatal("Database connection failed", zap.Error(err))
 	}
 
+	migrator, err := migrations.NewMigrator(conn)
+	if err != nil {
+		logger.Fatal("Failed to load migrations", zap.Error(err))
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err = runMigrateCommand(context.Background(), migrator, os.Args[2:])
+		if err != nil {
+			logger.Fatal("Migration command failed", zap.Error(err))
+		}
+		return
+	}
+
+	if config.Config.DbAutoMigrate {
+		logger.Info("Applying database migrations...")
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			logger.Fatal("Failed to apply migrations", zap.Error(err))
+		}
+		logger.Info("Database migrations applied", zap.Int("count", len(applied)))
+	}
+
+	store, err := newStorage()
+	if err != nil {
+		logger.Fatal("Failed to init media storage", zap.Error(err))
+	}
+	// Обработчики работают с хранилищем через кэш, чтобы удалённые файлы пропадали и из раздачи
+	mediaStorage, err := imaging.NewCache(config.Config.MediaCacheDir, store)
+	if err != nil {
+		logger.Fatal("Failed to init media cache", zap.Error(err))
+	}
+
 	r.Use(func(c *gin.Context) {
 		c.Set("db", conn)
 		c.Next()
@@ -98,164 +136,107 @@ func main() {
 	rolesRepository := repositories.NewRolesRepository(conn)
 	searchRepository  := repositories.NewSearchRepository(conn)
 	mediaRepository := repositories.NewMediaRepository(conn)
+	videoAssetsRepository := repositories.NewVideoAssetsRepository(conn)
 
 	homepageRepository := repositories.NewHomepageRepository(conn)
 	watchlistRepository := repositories.NewWatchlistRepository(conn)
+	revokedTokensRepository := repositories.NewRevokedTokensRepository(conn)
+	refreshTokensRepository := repositories.NewRefreshTokensRepository(conn)
+	suggestionsRepository := repositories.NewSuggestionsRepository(conn)
+	playbackRepository := repositories.NewPlaybackRepository(conn)
 
 	moviesHandler := admin.NewMoviesHandler(moviesRepository, movieTypesRepository, genresRepository,  agesRepository, categoriesRepository)
 	recommendationsHandler := admin.NewRecommendationsHandler(recommendationsRepository)
 	contentsHandler := admin.NewContentsHandler(seasonsRepository, episodesRepository)
+	videoAssetsHandler := admin.NewVideoAssetsHandler(videoAssetsRepository, episodesRepository)
 	usersHandler := admin.NewUsersHandler(usersRepository)
 	movieTypesHandler := admin.NewMovieTypesHandler(movieTypesRepository)
-	agesHandler := admin.NewAgesHandler(agesRepository)
-	genresHandler := admin.NewGenresHandler(genresRepository)
+	agesHandler := admin.NewAgesHandler(agesRepository, mediaStorage)
+	genresHandler := admin.NewGenresHandler(genresRepository, mediaStorage)
 	categoriesHandler := admin.NewCategoriesHandler(categoriesRepository)
 	rolesHandler := admin.NewRolesHandler(rolesRepository)
 	searchHandler := admin.NewSearchHandler(searchRepository)
-	mediaHandler := admin.NewMediaHandler(mediaRepository)
+	mediaHandler := admin.NewMediaHandler(mediaRepository, mediaStorage)
+	adminHomepageHandler := admin.NewHomepageHandler(homepageRepository)
 
-	HomepageHandler := public.NewHomepageHandler(homepageRepository, moviesRepository, genresRepository, categoriesRepository, agesRepository)
+	HomepageHandler := public.NewHomepageHandler(homepageRepository, moviesRepository, genresRepository, categoriesRepository, agesRepository, mediaStorage)
 
-	authHandler := public.NewAuthHandlers(usersRepository)
+	authHandler := public.NewAuthHandlers(usersRepository, revokedTokensRepository, refreshTokensRepository)
 	profilesHandler := public.NewProfilesHandler(usersRepository)
-	watchlistHandler := public.NewWatchlistHandler(watchlistRepository)
-	googleAuthHandler := public.NewAuthHandlers(usersRepository)
+	watchlistHandler := public.NewWatchlistHandler(watchlistRepository, mediaStorage)
+	suggestHandler := public.NewSuggestHandler(suggestionsRepository)
+	publicMoviesHandler := public.NewMoviesHandler(moviesRepository, watchlistRepository, playbackRepository, videoAssetsRepository, mediaStorage)
+	progressHandler := public.NewProgressHandler(playbackRepository, moviesRepository, seasonsRepository, episodesRepository, mediaStorage)
+	publicMediaHandler := public.NewMediaHandler(mediaStorage)
+	googleAuthHandler := public.NewAuthHandlers(usersRepository, revokedTokensRepository, refreshTokensRepository)
+
+	jobs.StartTokensSweeper(context.Background(), revokedTokensRepository, refreshTokensRepository, config.Config.TokensSweepInterval)
+	jobs.StartMoviesPublisher(context.Background(), moviesRepository, config.Config.MoviesPublishInterval)
+	jobs.StartSuggestionsRefresher(context.Background(), suggestionsRepository, config.Config.SuggestionsRefreshInterval)
+	jobs.StartMediaCollector(context.Background(), mediaRepository, mediaStorage, config.Config.MediaGCInterval, config.Config.MediaGCGracePeriod)
+	jobs.StartMediaCacheTrimmer(context.Background(), mediaStorage, config.Config.MediaCacheTrimInterval, config.Config.MediaCacheMaxBytes)
 
 	authorized := r.Group("")
 	authorized.Use(middlewares.AuthMiddleware)
 
+	authorized.GET("/profile/me", profilesHandler.UserProfile)
+	authorized.PUT("/profile/me", profilesHandler.Update)
+	authorized.PUT("/profile/me/password", profilesHandler.ChangePassword)
 	authorized.GET("/profile/:id", profilesHandler.UserProfile)
 	authorized.PUT("/profile/:id", profilesHandler.Update)
 	authorized.PUT("/profile/changepassword/:id", profilesHandler.ChangePassword)
 
 	authorized.GET("/homepage", HomepageHandler.GetMainScreen)
 	authorized.GET("/search", HomepageHandler.SearchMovies)
+	authorized.GET("/search/suggest", suggestHandler.Suggest)
 	authorized.GET("/search/:category_id", HomepageHandler.GetMoviesByCategory)
 
+	authorized.GET("/movies/:id", publicMoviesHandler.GetMovie)
+
 	authorized.POST("/watchlist/:movie_id", watchlistHandler.AddToWatchlist)
 	authorized.GET("/watchlist", watchlistHandler.GetWatchlist)
 	authorized.DELETE("/watchlist/:movie_id", watchlistHandler.RemoveFromWatchlist)
 	authorized.GET("/watchlist/:movie_id", watchlistHandler.IsInWatchlist)
 
+	authorized.GET("/progress/continue-watching", progressHandler.GetContinueWatching)
+	authorized.GET("/progress/movies/:movie_id", progressHandler.GetMovieProgress)
+	authorized.PUT("/progress/movies/:movie_id", progressHandler.ReportMovieProgress)
+	authorized.DELETE("/progress/movies/:movie_id", progressHandler.ResetMovieProgress)
+	authorized.GET("/progress/movies/:movie_id/next", progressHandler.GetNextEpisode)
+	authorized.PUT("/progress/episodes/:episode_id", progressHandler.ReportEpisodeProgress)
+
 	authorized.POST("/public/auth/signOut", authHandler.SignOut)
 
 	permitted := r.Group("")
 	permitted.Use(middlewares.AuthMiddleware)
-	permitted.Use(middlewares.CheckPermissionMiddleware)
-	
-	// Фильмы
-	movies := permitted.Group("/admin/movies")
-	{
-		movies.GET("", moviesHandler.FindAll)
-		movies.POST("", moviesHandler.Create)
-		movies.GET("/:id", moviesHandler.FindById)
-		movies.PUT("/:id", moviesHandler.Update)
-		movies.DELETE("/:id", moviesHandler.Delete)
-	
-		seasons := movies.Group("/:id/seasons")
-		{
-			seasons.POST("", contentsHandler.AddSeasonsAndEpisodes)
-			seasons.PUT("/:seasonId/edit", contentsHandler.UpdateSeason)
-			seasons.DELETE("/:seasonId", contentsHandler.DeleteSeason)
-		}
-	}
-
-	// Сезоны и эпизоды
-	seasons := permitted.Group("/admin/seasons")
-	{
-		seasons.PUT("/:seasonId/episodes/:episodeId", contentsHandler.UpdateEpisode)
-		seasons.DELETE("/:seasonId/episodes/:episodeId", contentsHandler.DeleteEpisode)
-	}
 
-	// Обложка и Скриншоты
-	media := permitted.Group("/admin/media")
-	{
-		media.GET("/movies/:id", mediaHandler.GetMovieMedia)       
-		media.PATCH("/movies/:id", mediaHandler.UploadMovieMedia)             
-		media.POST("/movies/:id/media", mediaHandler.UploadSingleMovieMedia) 
-		media.DELETE("/movies/:id/media", mediaHandler.DeleteMovieMedia)  
-	}
-	
-	
-	// Рекомендации
-	recommendations := permitted.Group("/admin/recommendations")
-	{
-		recommendations.GET("", recommendationsHandler.FindAll)
-		recommendations.POST("", recommendationsHandler.Create)
-		recommendations.GET("/:id", recommendationsHandler.FindById)
-		recommendations.DELETE("/:id", recommendationsHandler.Delete)
-	}
-	
-	// Типы фильмов
-	movieTypes := permitted.Group("/admin/movieTypes")
-	{
-		movieTypes.GET("", movieTypesHandler.FindAll)
-		movieTypes.POST("", movieTypesHandler.Create)
-		movieTypes.GET("/:id", movieTypesHandler.FindById)
-		movieTypes.PUT("/:id", movieTypesHandler.Update)
-		movieTypes.DELETE("/:id", movieTypesHandler.Delete)
-	}
-	
-	// Категории
-	categories := permitted.Group("/admin/categories")
-	{
-		categories.GET("", categoriesHandler.FindAll)
-		categories.POST("", categoriesHandler.Create)
-		categories.GET("/:id", categoriesHandler.FindById)
-		categories.PUT("/:id", categoriesHandler.Update)
-		categories.DELETE("/:id", categoriesHandler.Delete)
-	}
-	
-	// Пользователи
-	users := permitted.Group("/admin/users")
-	{
-		users.GET("", usersHandler.FindAll)
-		users.GET("/:id", usersHandler.FindById)
-		users.PUT("/:id/role", usersHandler.AssignRole)
-		users.DELETE("/:id", usersHandler.Delete)
-	}
-	
-	// Роли
-	roles := permitted.Group("/admin/roles")
-	{
-		roles.GET("", rolesHandler.FindAll)
-		roles.POST("", rolesHandler.Create)
-		roles.GET("/:id", rolesHandler.FindById)
-		roles.PUT("/:id", rolesHandler.Update)
-		roles.DELETE("/:id", rolesHandler.Delete)
-	}
-	
-	// Жанры
-	genres := permitted.Group("/admin/genres")
-	{
-		genres.GET("", genresHandler.FindAll)
-		genres.POST("", genresHandler.Create)
-		genres.GET("/:id", genresHandler.FindById)
-		genres.PUT("/:id", genresHandler.Update)
-		genres.DELETE("/:id", genresHandler.Delete)
-	}
-	
-	// Возрастные ограничения
-	ages := permitted.Group("/admin/ages")
-	{
-		ages.GET("", agesHandler.FindAll)
-		ages.POST("", agesHandler.Create)
-		ages.GET("/:id", agesHandler.FindById)
-		ages.PUT("/:id", agesHandler.Update)
-		ages.DELETE("/:id", agesHandler.Delete)
-	}
-	
-	// Поиск
-	permitted.GET("/admin/search", searchHandler.SearchAll)
-	
+	registerAdminRoutes(middlewares.NewPermittedGroup(permitted), adminHandlers{
+		movies:          moviesHandler,
+		contents:        contentsHandler,
+		videoAssets:     videoAssetsHandler,
+		media:           mediaHandler,
+		recommendations: recommendationsHandler,
+		movieTypes:      movieTypesHandler,
+		categories:      categoriesHandler,
+		users:           usersHandler,
+		roles:           rolesHandler,
+		genres:          genresHandler,
+		ages:            agesHandler,
+		search:          searchHandler,
+		homepage:        adminHomepageHandler,
+	})
 
 	unauthorized := r.Group("")
 	unauthorized.POST("/auth/signUp", authHandler.SignUp)
 	unauthorized.POST("/auth/signIn", authHandler.SignIn)
+	unauthorized.POST("/auth/refresh", authHandler.Refresh)
 
 	unauthorized.GET("/auth/google", googleAuthHandler.GoogleLogin)
 	unauthorized.GET("/auth/google/callback", authHandler.GoogleCallback)
 
+	unauthorized.GET("/media/*key", publicMediaHandler.ServeMedia)
+	unauthorized.HEAD("/media/*key", publicMediaHandler.ServeMedia)
+
 	docs.SwaggerInfo.BasePath = "/"
 	unauthorized.GET("/swagger/*any", swagger.WrapHandler(swaggerfiles.Handler))
 
@@ -272,6 +253,20 @@ func main() {
 func loadConfig() error {
 	viper.SetConfigFile(".env")
     viper.AutomaticEnv()
+	viper.SetDefault("JWT_EXPIRE_DURATION", 15*time.Minute)
+	viper.SetDefault("JWT_REFRESH_EXPIRE_DURATION", 30*24*time.Hour)
+	viper.SetDefault("TOKENS_SWEEP_INTERVAL", time.Hour)
+	viper.SetDefault("SUGGESTIONS_REFRESH_INTERVAL", 5*time.Minute)
+	viper.SetDefault("MOVIES_PUBLISH_INTERVAL", time.Minute)
+	viper.SetDefault("MEDIA_GC_INTERVAL", 24*time.Hour)
+	viper.SetDefault("MEDIA_GC_GRACE_PERIOD", 24*time.Hour)
+	viper.SetDefault("MEDIA_CACHE_TRIM_INTERVAL", time.Hour)
+	viper.SetDefault("MEDIA_CACHE_MAX_BYTES", int64(5<<30))
+	viper.SetDefault("STORAGE_DRIVER", "local")
+	viper.SetDefault("STORAGE_LOCAL_DIR", "images")
+	viper.SetDefault("MEDIA_BASE_URL", "/media/")
+	viper.SetDefault("MEDIA_CACHE_DIR", "cache/media")
+	viper.SetDefault("S3_PRESIGN_EXPIRY", 15*time.Minute)
 
 	err := viper.ReadInConfig()
 	if err != nil {
@@ -289,6 +284,27 @@ func loadConfig() error {
 	return nil
 }
 
+// newStorage создаёт хранилище медиафайлов по STORAGE_DRIVER
+func newStorage() (storage.Storage, error) {
+	switch config.Config.StorageDriver {
+	case "local":
+		return storage.NewLocal(config.Config.StorageLocalDir, config.Config.MediaBaseUrl)
+	case "s3":
+		return storage.NewS3(storage.S3Config{
+			Endpoint:      config.Config.S3Endpoint,
+			Region:        config.Config.S3Region,
+			Bucket:        config.Config.S3Bucket,
+			AccessKey:     config.Config.S3AccessKey,
+			SecretKey:     config.Config.S3SecretKey,
+			UsePathStyle:  config.Config.S3UsePathStyle,
+			PublicBaseURL: config.Config.S3PublicBaseUrl,
+			PresignExpiry: config.Config.S3PresignExpiry,
+		})
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q, expected local or s3", config.Config.StorageDriver)
+	}
+}
+
 func connectToDb() (*pgxpool.Pool, error) {
 	conn, err := pgxpool.New(context.Background(), config.Config.DbConnectionString)
 	if err != nil {
@@ -27,7 +27,8 @@ func AuthMiddleware(c *gin.Context) {
 	}
 
 	tokenString := strings.Split(authHeader, "Bearer ")[1]
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+	claims := &jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		return []byte(config.Config.JwtSecretKey), nil
 	})
 
@@ -46,75 +47,57 @@ func AuthMiddleware(c *gin.Context) {
 		return
 	}
 
-	userId, _ := strconv.Atoi(subject)
-	logger.Info("Token validated", zap.Int("userId", userId))
-	c.Set("userId", userId)
-	c.Next()
-}
-
-func CheckPermissionMiddleware(c *gin.Context) {
-	logger := logger.GetLogger()
-	userId, exists := c.Get("userId")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.NewApiError("user not found"))
-		c.Abort()
-		return
-	}
-	logger.Info("User ID found in context", zap.Int("userId", userId.(int)))
-
-	// Получаем соединение с базой из контекста
-	db, exists := c.Get("db")
-	if !exists {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("database connection not found"))
+	// Токены без jti нельзя отозвать, поэтому не принимаем их
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		logger.Warn("Token without id or expiration", zap.String("subject", subject))
+		c.JSON(http.StatusUnauthorized, models.NewApiError("invalid token"))
 		c.Abort()
 		return
 	}
 
-	pool, ok := db.(*pgxpool.Pool)
+	pool, ok := dbFromContext(c)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("invalid database connection"))
-		c.Abort()
 		return
 	}
 
-	userRepo := repositories.NewUsersRepository(pool)
-	user, err := userRepo.FindById(c, userId.(int))
+	revoked, err := repositories.NewRevokedTokensRepository(pool).IsRevoked(c, claims.ID)
 	if err != nil {
-		logger.Error("User not found in database", zap.String("error", err.Error()))
-		c.JSON(http.StatusUnauthorized, models.NewApiError("user not found"))
+		logger.Error("Failed to check token revocation", zap.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("couldn't validate token"))
 		c.Abort()
 		return
 	}
-	logger.Info("User found", zap.Int("userId", user.Id), zap.String("userName", user.Name))
-
-	roleRepo := repositories.NewRolesRepository(pool)
-	role, err := roleRepo.FindById(c, user.RoleID)
-	if err != nil {
-		logger.Error("Role not found in database", zap.Int("roleId", user.RoleID), zap.String("error", err.Error()))
-		c.JSON(http.StatusUnauthorized, models.NewApiError("role not found"))
+	if revoked {
+		logger.Warn("Revoked token used", zap.String("subject", subject))
+		c.JSON(http.StatusUnauthorized, models.NewApiError("token has been revoked"))
 		c.Abort()
 		return
 	}
-	logger.Info("Role found", zap.Int("roleId", role.Id), zap.String("roleName", role.Name))
 
-	// Карта для проверки прав на редактирование
-	permissions := map[string]bool{
-		"/admin/categories": role.CanEditCategories,
-		"/admin/movies":   	 role.CanEditProjects,
-		"/admin/users":      role.CanEditUsers,
-		"/admin/roles":      role.CanEditRoles,
-		"/admin/genres":     role.CanEditGenres,
-		"/admin/ages":       role.CanEditAges,
+	userId, _ := strconv.Atoi(subject)
+	logger.Info("Token validated", zap.Int("userId", userId))
+	c.Set("userId", userId)
+	c.Set("tokenId", claims.ID)
+	c.Set("tokenExpiresAt", claims.ExpiresAt.Time)
+	c.Next()
+}
+
+// dbFromContext достаёт пул соединений, положенный в контекст в main.go.
+// При ошибке ответ уже отправлен и запрос прерван
+func dbFromContext(c *gin.Context) (*pgxpool.Pool, bool) {
+	db, exists := c.Get("db")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, models.NewApiError("database connection not found"))
+		c.Abort()
+		return nil, false
 	}
 
-	// Проверяем, есть ли разрешение на текущий маршрут
-	if permission, exists := permissions[c.Request.URL.Path]; exists && !permission {
-		c.JSON(http.StatusForbidden, models.NewApiError("user does not have permission to edit "+c.Request.URL.Path))
+	pool, ok := db.(*pgxpool.Pool)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.NewApiError("invalid database connection"))
 		c.Abort()
-		return
+		return nil, false
 	}
 
-	// Если роль имеет доступ, продолжаем выполнение
-	logger.Info("User has appropriate permissions")
-	c.Next()
-}
\ No newline at end of file
+	return pool, true
+}
@@ -0,0 +1,156 @@
+package middlewares
+
+import (
+	"net/http"
+	"ozinshe_production/logger"
+	"ozinshe_production/models"
+	"ozinshe_production/repositories"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PermittedGroup регистрирует маршруты вместе с правом, которое они требуют.
+// Проверка выполняется по шаблону совпавшего маршрута (c.FullPath()) и HTTP-методу,
+// поэтому вложенные пути вроде /admin/movies/:id/seasons проверяются так же, как корневые.
+// Маршрут группы без объявленного права всегда отклоняется
+type PermittedGroup struct {
+	group       *gin.RouterGroup
+	permissions map[string]string
+}
+
+func NewPermittedGroup(group *gin.RouterGroup) *PermittedGroup {
+	g := &PermittedGroup{group: group, permissions: make(map[string]string)}
+	group.Use(g.checkPermission)
+	return g
+}
+
+func (g *PermittedGroup) Group(relativePath string) *PermittedGroup {
+	return &PermittedGroup{group: g.group.Group(relativePath), permissions: g.permissions}
+}
+
+func (g *PermittedGroup) Handle(method, relativePath, permission string, handlers ...gin.HandlerFunc) {
+	fullPath := joinPaths(g.group.BasePath(), relativePath)
+	g.permissions[routeKey(method, fullPath)] = permission
+	g.group.Handle(method, relativePath, handlers...)
+}
+
+func (g *PermittedGroup) GET(relativePath, permission string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodGet, relativePath, permission, handlers...)
+}
+
+func (g *PermittedGroup) POST(relativePath, permission string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPost, relativePath, permission, handlers...)
+}
+
+func (g *PermittedGroup) PUT(relativePath, permission string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPut, relativePath, permission, handlers...)
+}
+
+func (g *PermittedGroup) PATCH(relativePath, permission string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPatch, relativePath, permission, handlers...)
+}
+
+func (g *PermittedGroup) DELETE(relativePath, permission string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodDelete, relativePath, permission, handlers...)
+}
+
+// Permission возвращает право, объявленное для метода и шаблона маршрута
+func (g *PermittedGroup) Permission(method, fullPath string) (string, bool) {
+	permission, exists := g.permissions[routeKey(method, fullPath)]
+	return permission, exists
+}
+
+func (g *PermittedGroup) checkPermission(c *gin.Context) {
+	logger := logger.GetLogger()
+
+	permission, declared := g.Permission(c.Request.Method, c.FullPath())
+	if !declared {
+		logger.Error("Route has no declared permission", zap.String("method", c.Request.Method), zap.String("route", c.FullPath()))
+		c.JSON(http.StatusForbidden, models.NewApiError("access to this route is not allowed"))
+		c.Abort()
+		return
+	}
+
+	allowed, ok := HasPermission(c, permission)
+	if !ok {
+		return
+	}
+
+	if !allowed {
+		logger.Warn("Permission denied", zap.Int("userId", c.GetInt("userId")), zap.String("permission", permission),
+			zap.String("method", c.Request.Method), zap.String("route", c.FullPath()))
+		c.JSON(http.StatusForbidden, models.NewApiError("user does not have permission "+permission))
+		c.Abort()
+		return
+	}
+
+	logger.Info("User has appropriate permissions", zap.String("permission", permission))
+	c.Next()
+}
+
+// HasPermission проверяет, содержит ли роль текущего пользователя указанное право.
+// Второе значение false означает, что ответ с ошибкой уже отправлен и запрос прерван
+func HasPermission(c *gin.Context, permission string) (bool, bool) {
+	permissions, ok := userPermissions(c)
+	if !ok {
+		return false, false
+	}
+	return slices.Contains(permissions, permission), true
+}
+
+// userPermissions загружает права роли текущего пользователя и кэширует их в контексте запроса.
+// При ошибке ответ уже отправлен и запрос прерван
+func userPermissions(c *gin.Context) ([]string, bool) {
+	logger := logger.GetLogger()
+
+	if cached, exists := c.Get("permissions"); exists {
+		if permissions, ok := cached.([]string); ok {
+			return permissions, true
+		}
+	}
+
+	userId, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.NewApiError("user not found"))
+		c.Abort()
+		return nil, false
+	}
+
+	// Получаем соединение с базой из контекста
+	pool, ok := dbFromContext(c)
+	if !ok {
+		return nil, false
+	}
+
+	permissions, err := repositories.NewRolesRepository(pool).FindPermissionsByUserId(c, userId.(int))
+	if err != nil {
+		logger.Error("Failed to load user permissions", zap.Int("userId", userId.(int)), zap.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, models.NewApiError("couldn't load permissions"))
+		c.Abort()
+		return nil, false
+	}
+
+	c.Set("permissions", permissions)
+	return permissions, true
+}
+
+func routeKey(method, fullPath string) string {
+	return method + " " + fullPath
+}
+
+// joinPaths повторяет склейку путей gin, чтобы ключ совпадал с c.FullPath()
+func joinPaths(absolutePath, relativePath string) string {
+	if relativePath == "" {
+		return absolutePath
+	}
+
+	finalPath := path.Join(absolutePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(finalPath, "/") {
+		return finalPath + "/"
+	}
+	return finalPath
+}
@@ -0,0 +1,84 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newPermittedTestRouter(permissions []string) (*gin.Engine, *PermittedGroup) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	group := r.Group("")
+	group.Use(func(c *gin.Context) {
+		c.Set("userId", 1)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	return r, NewPermittedGroup(group)
+}
+
+func ok(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+func TestPermittedGroupChecksNestedRouteTemplates(t *testing.T) {
+	cases := []struct {
+		name        string
+		method      string
+		url         string
+		permissions []string
+		want        int
+	}{
+		{"root route allowed", http.MethodGet, "/admin/movies", []string{"movies:read"}, http.StatusOK},
+		{"root route denied", http.MethodGet, "/admin/movies", nil, http.StatusForbidden},
+		{"nested route allowed", http.MethodDelete, "/admin/movies/5/seasons/2", []string{"movies:write"}, http.StatusOK},
+		{"nested route denied for read", http.MethodDelete, "/admin/movies/5/seasons/2", []string{"movies:read"}, http.StatusForbidden},
+		{"same path other method", http.MethodGet, "/admin/movies/5/seasons/2", []string{"movies:read"}, http.StatusOK},
+		{"undeclared route denied", http.MethodGet, "/admin/raw", []string{"movies:read", "movies:write"}, http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, permitted := newPermittedTestRouter(tc.permissions)
+
+			movies := permitted.Group("/admin/movies")
+			movies.GET("", "movies:read", ok)
+			seasons := movies.Group("/:id/seasons")
+			seasons.GET("/:seasonId", "movies:read", ok)
+			seasons.DELETE("/:seasonId", "movies:write", ok)
+
+			// Маршрут, зарегистрированный в обход PermittedGroup, не имеет объявленного права
+			permitted.group.GET("/admin/raw", ok)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tc.method, tc.url, nil))
+
+			if w.Code != tc.want {
+				t.Errorf("%s %s: got %d, want %d", tc.method, tc.url, w.Code, tc.want)
+			}
+		})
+	}
+}
+
+func TestJoinPathsMatchesGinFullPath(t *testing.T) {
+	cases := []struct {
+		base, relative, want string
+	}{
+		{"/admin/movies", "", "/admin/movies"},
+		{"/admin/movies", "/:id", "/admin/movies/:id"},
+		{"/admin/movies/:id/seasons", "/:seasonId/edit", "/admin/movies/:id/seasons/:seasonId/edit"},
+		{"/", "/admin/search", "/admin/search"},
+		{"/admin", "/media/", "/admin/media/"},
+	}
+
+	for _, tc := range cases {
+		if got := joinPaths(tc.base, tc.relative); got != tc.want {
+			t.Errorf("joinPaths(%q, %q) = %q, want %q", tc.base, tc.relative, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS recommended_movies;
+DROP TABLE IF EXISTS watchlist;
+DROP TABLE IF EXISTS episodes;
+DROP TABLE IF EXISTS seasons;
+DROP TABLE IF EXISTS movie_ages;
+DROP TABLE IF EXISTS movie_categories;
+DROP TABLE IF EXISTS movie_genres;
+DROP TABLE IF EXISTS movies;
+DROP TABLE IF EXISTS ages;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS genres;
+DROP TABLE IF EXISTS movie_types;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,117 @@
+CREATE TABLE IF NOT EXISTS roles (
+    id                  SERIAL PRIMARY KEY,
+    name                TEXT NOT NULL UNIQUE,
+    can_edit_projects   BOOLEAN NOT NULL DEFAULT FALSE,
+    can_edit_categories BOOLEAN NOT NULL DEFAULT FALSE,
+    can_edit_users      BOOLEAN NOT NULL DEFAULT FALSE,
+    can_edit_roles      BOOLEAN NOT NULL DEFAULT FALSE,
+    can_edit_genres     BOOLEAN NOT NULL DEFAULT FALSE,
+    can_edit_ages       BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- Роль с id = 2 используется как роль по умолчанию для новых пользователей
+INSERT INTO roles (id, name, can_edit_projects, can_edit_categories, can_edit_users, can_edit_roles, can_edit_genres, can_edit_ages)
+VALUES (1, 'admin', TRUE, TRUE, TRUE, TRUE, TRUE, TRUE),
+       (2, 'user', FALSE, FALSE, FALSE, FALSE, FALSE, FALSE)
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('roles', 'id'), (SELECT MAX(id) FROM roles));
+
+CREATE TABLE IF NOT EXISTS users (
+    id           SERIAL PRIMARY KEY,
+    email        TEXT NOT NULL UNIQUE,
+    password     TEXT NOT NULL DEFAULT '',
+    name         TEXT NOT NULL DEFAULT '',
+    phone_number TEXT NOT NULL DEFAULT '',
+    birth_date   DATE NOT NULL DEFAULT '0001-01-01',
+    role_id      INT NOT NULL DEFAULT 2 REFERENCES roles (id) ON DELETE SET DEFAULT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS movie_types (
+    id    SERIAL PRIMARY KEY,
+    title TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS genres (
+    id         SERIAL PRIMARY KEY,
+    title      TEXT NOT NULL,
+    poster_url TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id    SERIAL PRIMARY KEY,
+    title TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ages (
+    id         SERIAL PRIMARY KEY,
+    title      TEXT NOT NULL,
+    poster_url TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS movies (
+    id            SERIAL PRIMARY KEY,
+    title         TEXT NOT NULL,
+    release_year  INT NOT NULL DEFAULT 0,
+    runtime       INT NOT NULL DEFAULT 0,
+    keywords      TEXT[],
+    description   TEXT NOT NULL DEFAULT '',
+    director      TEXT NOT NULL DEFAULT '',
+    producer      TEXT NOT NULL DEFAULT '',
+    cover         TEXT,
+    screenshots   TEXT[],
+    movie_type_id INT NOT NULL REFERENCES movie_types (id)
+);
+
+CREATE TABLE IF NOT EXISTS movie_genres (
+    movie_id INT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    genre_id INT NOT NULL REFERENCES genres (id) ON DELETE CASCADE,
+    PRIMARY KEY (movie_id, genre_id)
+);
+
+CREATE TABLE IF NOT EXISTS movie_categories (
+    movie_id    INT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    category_id INT NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (movie_id, category_id)
+);
+
+CREATE TABLE IF NOT EXISTS movie_ages (
+    movie_id INT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    age_id   INT NOT NULL REFERENCES ages (id) ON DELETE CASCADE,
+    PRIMARY KEY (movie_id, age_id)
+);
+
+CREATE TABLE IF NOT EXISTS seasons (
+    id       SERIAL PRIMARY KEY,
+    movie_id INT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    number   INT NOT NULL,
+    UNIQUE (movie_id, number)
+);
+
+CREATE TABLE IF NOT EXISTS episodes (
+    id        SERIAL PRIMARY KEY,
+    season_id INT NOT NULL REFERENCES seasons (id) ON DELETE CASCADE,
+    number    INT NOT NULL,
+    video_url TEXT NOT NULL DEFAULT '',
+    UNIQUE (season_id, number)
+);
+
+CREATE TABLE IF NOT EXISTS watchlist (
+    user_id    INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    movie_id   INT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE TABLE IF NOT EXISTS recommended_movies (
+    id       SERIAL PRIMARY KEY,
+    movie_id INT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_movies_movie_type_id ON movies (movie_type_id);
+CREATE INDEX IF NOT EXISTS idx_movie_genres_genre_id ON movie_genres (genre_id);
+CREATE INDEX IF NOT EXISTS idx_movie_categories_category_id ON movie_categories (category_id);
+CREATE INDEX IF NOT EXISTS idx_movie_ages_age_id ON movie_ages (age_id);
+CREATE INDEX IF NOT EXISTS idx_recommended_movies_position ON recommended_movies (position);
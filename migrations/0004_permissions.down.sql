@@ -0,0 +1,18 @@
+ALTER TABLE roles
+    ADD COLUMN can_edit_projects   BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN can_edit_categories BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN can_edit_users      BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN can_edit_roles      BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN can_edit_genres     BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN can_edit_ages       BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE roles r SET
+    can_edit_projects   = EXISTS (SELECT 1 FROM role_permissions rp JOIN permissions p ON p.id = rp.permission_id WHERE rp.role_id = r.id AND p.name = 'movies:write'),
+    can_edit_categories = EXISTS (SELECT 1 FROM role_permissions rp JOIN permissions p ON p.id = rp.permission_id WHERE rp.role_id = r.id AND p.name = 'categories:write'),
+    can_edit_users      = EXISTS (SELECT 1 FROM role_permissions rp JOIN permissions p ON p.id = rp.permission_id WHERE rp.role_id = r.id AND p.name = 'users:write'),
+    can_edit_roles      = EXISTS (SELECT 1 FROM role_permissions rp JOIN permissions p ON p.id = rp.permission_id WHERE rp.role_id = r.id AND p.name = 'roles:write'),
+    can_edit_genres     = EXISTS (SELECT 1 FROM role_permissions rp JOIN permissions p ON p.id = rp.permission_id WHERE rp.role_id = r.id AND p.name = 'genres:write'),
+    can_edit_ages       = EXISTS (SELECT 1 FROM role_permissions rp JOIN permissions p ON p.id = rp.permission_id WHERE rp.role_id = r.id AND p.name = 'ages:write');
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
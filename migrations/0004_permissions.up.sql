@@ -0,0 +1,58 @@
+CREATE TABLE IF NOT EXISTS permissions (
+    id          SERIAL PRIMARY KEY,
+    name        TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       INT NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id INT NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('movies:read', 'View movies, seasons and episodes'),
+    ('movies:write', 'Create, edit and delete movies, seasons and episodes'),
+    ('media:read', 'View movie covers and screenshots'),
+    ('media:write', 'Upload and delete movie covers and screenshots'),
+    ('recommendations:read', 'View recommended movies'),
+    ('recommendations:write', 'Manage recommended movies'),
+    ('movie_types:read', 'View movie types'),
+    ('movie_types:write', 'Manage movie types'),
+    ('categories:read', 'View categories'),
+    ('categories:write', 'Manage categories'),
+    ('genres:read', 'View genres'),
+    ('genres:write', 'Manage genres'),
+    ('ages:read', 'View age ratings'),
+    ('ages:write', 'Manage age ratings'),
+    ('users:read', 'View users'),
+    ('users:write', 'Edit, delete users and assign roles'),
+    ('roles:read', 'View roles and permissions'),
+    ('roles:write', 'Manage roles and their permissions'),
+    ('search:read', 'Use admin search')
+ON CONFLICT (name) DO NOTHING;
+
+-- Переносим старые флаги can_edit_* в набор прав
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON
+       (r.can_edit_projects AND p.name IN ('movies:read', 'movies:write', 'media:read', 'media:write',
+                                           'recommendations:read', 'recommendations:write',
+                                           'movie_types:read', 'movie_types:write'))
+    OR (r.can_edit_categories AND p.name IN ('categories:read', 'categories:write'))
+    OR (r.can_edit_users AND p.name IN ('users:read', 'users:write'))
+    OR (r.can_edit_roles AND p.name IN ('roles:read', 'roles:write'))
+    OR (r.can_edit_genres AND p.name IN ('genres:read', 'genres:write'))
+    OR (r.can_edit_ages AND p.name IN ('ages:read', 'ages:write'))
+    OR ((r.can_edit_projects OR r.can_edit_categories OR r.can_edit_users
+         OR r.can_edit_roles OR r.can_edit_genres OR r.can_edit_ages) AND p.name = 'search:read')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE roles
+    DROP COLUMN can_edit_projects,
+    DROP COLUMN can_edit_categories,
+    DROP COLUMN can_edit_users,
+    DROP COLUMN can_edit_roles,
+    DROP COLUMN can_edit_genres,
+    DROP COLUMN can_edit_ages;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_movies_created_at_id;
+DROP INDEX IF EXISTS idx_movies_release_year_id;
+DROP INDEX IF EXISTS idx_movies_title_id;
+
+ALTER TABLE movies DROP COLUMN IF EXISTS created_at;
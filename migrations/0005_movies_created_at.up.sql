@@ -0,0 +1,6 @@
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- Индексы под keyset-пагинацию: сортировка всегда дополняется id для стабильного порядка
+CREATE INDEX IF NOT EXISTS idx_movies_title_id ON movies (title, id);
+CREATE INDEX IF NOT EXISTS idx_movies_release_year_id ON movies (release_year, id);
+CREATE INDEX IF NOT EXISTS idx_movies_created_at_id ON movies (created_at, id);
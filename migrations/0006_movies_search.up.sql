@@ -0,0 +1,41 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Казахского словаря в PostgreSQL нет, поэтому каждое поле индексируется трижды:
+-- simple (казахский и точные слова), russian и english со стеммингом
+CREATE OR REPLACE FUNCTION movies_multilang_tsvector(content TEXT, weight "char")
+RETURNS tsvector
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT setweight(to_tsvector('simple'::regconfig, COALESCE(content, '')), weight)
+        || setweight(to_tsvector('russian'::regconfig, COALESCE(content, '')), weight)
+        || setweight(to_tsvector('english'::regconfig, COALESCE(content, '')), weight)
+$$;
+
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+-- Веса: A — название, B — ключевые слова, C — режиссёр и продюсер, D — описание
+CREATE OR REPLACE FUNCTION movies_search_vector_update()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    NEW.search_vector :=
+        movies_multilang_tsvector(NEW.title, 'A')
+        || movies_multilang_tsvector(array_to_string(NEW.keywords, ' '), 'B')
+        || movies_multilang_tsvector(NEW.director || ' ' || NEW.producer, 'C')
+        || movies_multilang_tsvector(NEW.description, 'D');
+    RETURN NEW;
+END
+$$;
+
+DROP TRIGGER IF EXISTS movies_search_vector_update ON movies;
+CREATE TRIGGER movies_search_vector_update
+    BEFORE INSERT OR UPDATE OF title, keywords, director, producer, description ON movies
+    FOR EACH ROW EXECUTE FUNCTION movies_search_vector_update();
+
+-- Заполняем вектор для уже существующих фильмов
+UPDATE movies SET title = title;
+
+CREATE INDEX IF NOT EXISTS idx_movies_search_vector ON movies USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_movies_title_trgm ON movies USING GIN (title gin_trgm_ops);
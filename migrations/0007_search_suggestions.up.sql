@@ -0,0 +1,28 @@
+-- Индекс подсказок для автодополнения: названия, ключевые слова, люди, категории и жанры.
+-- weight поднимает популярные варианты выше
+CREATE MATERIALIZED VIEW IF NOT EXISTS search_suggestions AS
+    SELECT 'title' AS kind, m.id AS ref_id, m.title AS value, lower(m.title) AS normalized,
+           (SELECT COUNT(*) FROM watchlist w WHERE w.movie_id = m.id)::INT AS weight
+    FROM movies m
+    UNION ALL
+    SELECT 'keyword', 0, min(k), lower(k), COUNT(DISTINCT m.id)::INT
+    FROM movies m, unnest(m.keywords) k
+    WHERE btrim(k) <> ''
+    GROUP BY lower(k)
+    UNION ALL
+    SELECT 'person', 0, min(p), lower(p), COUNT(DISTINCT m.id)::INT
+    FROM movies m, unnest(ARRAY[m.director, m.producer]) p
+    WHERE btrim(p) <> ''
+    GROUP BY lower(p)
+    UNION ALL
+    SELECT 'category', c.id, c.title, lower(c.title),
+           (SELECT COUNT(*) FROM movie_categories mc WHERE mc.category_id = c.id)::INT
+    FROM categories c
+    UNION ALL
+    SELECT 'genre', g.id, g.title, lower(g.title),
+           (SELECT COUNT(*) FROM movie_genres mg WHERE mg.genre_id = g.id)::INT
+    FROM genres g;
+
+-- Уникальный индекс нужен для REFRESH MATERIALIZED VIEW CONCURRENTLY
+CREATE UNIQUE INDEX IF NOT EXISTS idx_search_suggestions_unique ON search_suggestions (kind, ref_id, normalized);
+CREATE INDEX IF NOT EXISTS idx_search_suggestions_prefix ON search_suggestions (normalized text_pattern_ops);
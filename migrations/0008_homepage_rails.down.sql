@@ -0,0 +1,4 @@
+DELETE FROM permissions WHERE name IN ('homepage:read', 'homepage:write');
+
+DROP TABLE IF EXISTS homepage_rail_movies;
+DROP TABLE IF EXISTS homepage_rails;
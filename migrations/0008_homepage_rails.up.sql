@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS homepage_rails (
+    id            SERIAL PRIMARY KEY,
+    type          TEXT NOT NULL CHECK (type IN ('category', 'genre', 'hand_picked', 'continue_watching', 'new_releases')),
+    title         TEXT NOT NULL,
+    position      INT NOT NULL DEFAULT 0,
+    size          INT NOT NULL DEFAULT 12 CHECK (size BETWEEN 1 AND 50),
+    category_id   INT REFERENCES categories (id) ON DELETE CASCADE,
+    genre_id      INT REFERENCES genres (id) ON DELETE CASCADE,
+    visible_from  TIMESTAMPTZ,
+    visible_until TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (type <> 'category' OR category_id IS NOT NULL),
+    CHECK (type <> 'genre' OR genre_id IS NOT NULL),
+    CHECK (visible_from IS NULL OR visible_until IS NULL OR visible_from < visible_until)
+);
+
+CREATE INDEX IF NOT EXISTS idx_homepage_rails_position ON homepage_rails (position, id);
+
+-- Фильмы лент типа hand_picked в заданном редактором порядке
+CREATE TABLE IF NOT EXISTS homepage_rail_movies (
+    rail_id  INT NOT NULL REFERENCES homepage_rails (id) ON DELETE CASCADE,
+    movie_id INT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    PRIMARY KEY (rail_id, movie_id)
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('homepage:read', 'View the homepage layout'),
+    ('homepage:write', 'Compose the homepage layout')
+ON CONFLICT (name) DO NOTHING;
+
+-- Главной страницей управляют те же редакторы, что и рекомендациями
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT rp.role_id, p.id
+FROM role_permissions rp
+JOIN permissions old ON old.id = rp.permission_id
+JOIN permissions p ON p.name = replace(old.name, 'recommendations:', 'homepage:')
+WHERE old.name IN ('recommendations:read', 'recommendations:write')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,48 @@
+ALTER TABLE movies
+    ADD COLUMN IF NOT EXISTS status       TEXT NOT NULL DEFAULT 'draft',
+    ADD COLUMN IF NOT EXISTS publish_at   TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMPTZ;
+
+-- Всё, что уже было на сайте, остаётся опубликованным
+UPDATE movies SET status = 'published', publish_at = created_at;
+
+ALTER TABLE movies
+    ADD CONSTRAINT movies_status_check CHECK (status IN ('draft', 'scheduled', 'published', 'archived')),
+    ADD CONSTRAINT movies_scheduled_publish_at_check CHECK (status <> 'scheduled' OR publish_at IS NOT NULL),
+    ADD CONSTRAINT movies_publish_window_check CHECK (publish_at IS NULL OR unpublish_at IS NULL OR publish_at < unpublish_at);
+
+CREATE INDEX IF NOT EXISTS idx_movies_status ON movies (status);
+-- Индексы для планировщика публикаций
+CREATE INDEX IF NOT EXISTS idx_movies_scheduled_publish_at ON movies (publish_at) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_movies_published_unpublish_at ON movies (unpublish_at) WHERE status = 'published' AND unpublish_at IS NOT NULL;
+
+-- Подсказки поиска строятся только по опубликованным фильмам
+DROP MATERIALIZED VIEW IF EXISTS search_suggestions;
+CREATE MATERIALIZED VIEW search_suggestions AS
+    SELECT 'title' AS kind, m.id AS ref_id, m.title AS value, lower(m.title) AS normalized,
+           (SELECT COUNT(*) FROM watchlist w WHERE w.movie_id = m.id)::INT AS weight
+    FROM movies m
+    WHERE m.status = 'published'
+    UNION ALL
+    SELECT 'keyword', 0, min(k), lower(k), COUNT(DISTINCT m.id)::INT
+    FROM movies m, unnest(m.keywords) k
+    WHERE m.status = 'published' AND btrim(k) <> ''
+    GROUP BY lower(k)
+    UNION ALL
+    SELECT 'person', 0, min(p), lower(p), COUNT(DISTINCT m.id)::INT
+    FROM movies m, unnest(ARRAY[m.director, m.producer]) p
+    WHERE m.status = 'published' AND btrim(p) <> ''
+    GROUP BY lower(p)
+    UNION ALL
+    SELECT 'category', c.id, c.title, lower(c.title),
+           (SELECT COUNT(*) FROM movie_categories mc JOIN movies m ON m.id = mc.movie_id
+            WHERE mc.category_id = c.id AND m.status = 'published')::INT
+    FROM categories c
+    UNION ALL
+    SELECT 'genre', g.id, g.title, lower(g.title),
+           (SELECT COUNT(*) FROM movie_genres mg JOIN movies m ON m.id = mg.movie_id
+            WHERE mg.genre_id = g.id AND m.status = 'published')::INT
+    FROM genres g;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_search_suggestions_unique ON search_suggestions (kind, ref_id, normalized);
+CREATE INDEX IF NOT EXISTS idx_search_suggestions_prefix ON search_suggestions (normalized text_pattern_ops);
@@ -0,0 +1,17 @@
+-- Позиция просмотра: одна запись на полнометражный фильм или на эпизод сериала
+CREATE TABLE IF NOT EXISTS playback_progress (
+    id               SERIAL PRIMARY KEY,
+    user_id          INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    movie_id         INT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    episode_id       INT REFERENCES episodes (id) ON DELETE CASCADE,
+    position_seconds INT NOT NULL CHECK (position_seconds >= 0),
+    duration_seconds INT NOT NULL CHECK (duration_seconds > 0),
+    watched          BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (position_seconds <= duration_seconds)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_playback_progress_movie ON playback_progress (user_id, movie_id) WHERE episode_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_playback_progress_episode ON playback_progress (user_id, episode_id) WHERE episode_id IS NOT NULL;
+-- Последние просмотры пользователя для «Продолжить просмотр»
+CREATE INDEX IF NOT EXISTS idx_playback_progress_user_updated ON playback_progress (user_id, movie_id, updated_at DESC);
@@ -0,0 +1,10 @@
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS screenshots TEXT[];
+
+UPDATE movies m
+SET screenshots = (
+    SELECT array_agg(mm.storage_key ORDER BY mm.position, mm.id)
+    FROM movie_media mm
+    WHERE mm.movie_id = m.id
+);
+
+DROP TABLE IF EXISTS movie_media;
@@ -0,0 +1,24 @@
+-- Галерея скриншотов фильма вместо movies.screenshots: у каждого кадра свой id, позиция,
+-- подпись, альтернативный текст и отметка главного кадра
+CREATE TABLE IF NOT EXISTS movie_media (
+    id          SERIAL PRIMARY KEY,
+    movie_id    INT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    storage_key TEXT NOT NULL,
+    position    INT NOT NULL CHECK (position > 0),
+    caption     TEXT NOT NULL DEFAULT '',
+    alt_text    TEXT NOT NULL DEFAULT '',
+    is_hero     BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- Проверяется в конце запроса, поэтому перестановка меняет все позиции одним UPDATE
+    CONSTRAINT movie_media_position_key UNIQUE (movie_id, position) DEFERRABLE INITIALLY IMMEDIATE
+);
+
+-- Не больше одного главного кадра у фильма
+CREATE UNIQUE INDEX IF NOT EXISTS idx_movie_media_hero ON movie_media (movie_id) WHERE is_hero;
+
+INSERT INTO movie_media (movie_id, storage_key, position)
+SELECT m.id, s.storage_key, ROW_NUMBER() OVER (PARTITION BY m.id ORDER BY s.ord)
+FROM movies m, unnest(m.screenshots) WITH ORDINALITY AS s(storage_key, ord)
+WHERE COALESCE(s.storage_key, '') <> '';
+
+ALTER TABLE movies DROP COLUMN IF EXISTS screenshots;
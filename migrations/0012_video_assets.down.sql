@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS video_audio_tracks;
+DROP TABLE IF EXISTS video_subtitles;
+DROP TABLE IF EXISTS video_renditions;
+DROP TABLE IF EXISTS video_assets;
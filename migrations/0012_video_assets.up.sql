@@ -0,0 +1,61 @@
+-- Видео эпизода или полнометражного фильма без сезонов: манифесты HLS/DASH и длительность
+CREATE TABLE IF NOT EXISTS video_assets (
+    id               SERIAL PRIMARY KEY,
+    episode_id       INT REFERENCES episodes (id) ON DELETE CASCADE,
+    movie_id         INT REFERENCES movies (id) ON DELETE CASCADE,
+    kind             TEXT NOT NULL DEFAULT 'main' CHECK (kind IN ('main', 'trailer')),
+    duration_seconds INT NOT NULL CHECK (duration_seconds > 0),
+    hls_url          TEXT NOT NULL DEFAULT '',
+    dash_url         TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (num_nonnulls(episode_id, movie_id) = 1),
+    CHECK (hls_url <> '' OR dash_url <> '')
+);
+
+CREATE INDEX IF NOT EXISTS idx_video_assets_episode_id ON video_assets (episode_id);
+CREATE INDEX IF NOT EXISTS idx_video_assets_movie_id ON video_assets (movie_id);
+-- Основное видео у эпизода или фильма одно, трейлеров может быть несколько
+CREATE UNIQUE INDEX IF NOT EXISTS idx_video_assets_episode_main ON video_assets (episode_id) WHERE kind = 'main';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_video_assets_movie_main ON video_assets (movie_id) WHERE kind = 'main';
+
+-- Качества внутри манифеста
+CREATE TABLE IF NOT EXISTS video_renditions (
+    id           SERIAL PRIMARY KEY,
+    asset_id     INT NOT NULL REFERENCES video_assets (id) ON DELETE CASCADE,
+    quality      TEXT NOT NULL,
+    width        INT NOT NULL CHECK (width > 0),
+    height       INT NOT NULL CHECK (height > 0),
+    bitrate_kbps INT NOT NULL CHECK (bitrate_kbps > 0),
+    codec        TEXT NOT NULL DEFAULT '',
+    url          TEXT NOT NULL DEFAULT '',
+    UNIQUE (asset_id, quality)
+);
+
+CREATE TABLE IF NOT EXISTS video_subtitles (
+    id         SERIAL PRIMARY KEY,
+    asset_id   INT NOT NULL REFERENCES video_assets (id) ON DELETE CASCADE,
+    language   TEXT NOT NULL CHECK (language IN ('kk', 'ru', 'en')),
+    label      TEXT NOT NULL DEFAULT '',
+    format     TEXT NOT NULL DEFAULT 'vtt' CHECK (format IN ('vtt', 'srt')),
+    url        TEXT NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE (asset_id, language)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_video_subtitles_default ON video_subtitles (asset_id) WHERE is_default;
+
+-- Язык аудио — любой код ISO 639 с необязательным регионом. На одном языке может быть
+-- несколько дорожек (дубляж, оригинал, комментарии), их различает подпись
+CREATE TABLE IF NOT EXISTS video_audio_tracks (
+    id         SERIAL PRIMARY KEY,
+    asset_id   INT NOT NULL REFERENCES video_assets (id) ON DELETE CASCADE,
+    language   TEXT NOT NULL CHECK (language ~ '^[a-z]{2,3}(-[A-Z]{2})?$'),
+    label      TEXT NOT NULL DEFAULT '',
+    codec      TEXT NOT NULL DEFAULT '',
+    channels   INT NOT NULL DEFAULT 2 CHECK (channels > 0),
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE (asset_id, language, label)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_video_audio_tracks_default ON video_audio_tracks (asset_id) WHERE is_default;
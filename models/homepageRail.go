@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	RailTypeCategory         = "category"
+	RailTypeGenre            = "genre"
+	RailTypeHandPicked       = "hand_picked"
+	RailTypeContinueWatching = "continue_watching"
+	RailTypeNewReleases      = "new_releases"
+
+	DefaultRailSize = 12
+	MaxRailSize     = 50
+)
+
+// HomepageRail — лента главной страницы, настроенная в админке
+type HomepageRail struct {
+	Id				int			`json:"id"`
+	Type			string		`json:"type"`
+	Title			string		`json:"title"`
+	Position		int			`json:"position"`
+	Size			int			`json:"size"`
+	CategoryId		*int		`json:"category_id"`
+	GenreId			*int		`json:"genre_id"`
+	MovieIds		[]int		`json:"movie_ids"`		// Только для hand_picked
+	VisibleFrom		*time.Time	`json:"visible_from"`
+	VisibleUntil	*time.Time	`json:"visible_until"`
+}
+
+// HomepageRailView — лента в том виде, в котором её получает клиент
+type HomepageRailView struct {
+	Id		int			`json:"id"`
+	Type	string		`json:"type"`
+	Title	string		`json:"title"`
+	Movies	[]MovieCard	`json:"movies"`
+}
+
+// Validate проверяет согласованность настроек ленты и подставляет размер по умолчанию.
+// Ссылки, которые не нужны типу ленты, сбрасываются
+func (r *HomepageRail) Validate() error {
+	if r.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+
+	if r.Size == 0 {
+		r.Size = DefaultRailSize
+	}
+	if r.Size < 1 || r.Size > MaxRailSize {
+		return fmt.Errorf("size must be between 1 and %d", MaxRailSize)
+	}
+
+	if r.VisibleFrom != nil && r.VisibleUntil != nil && !r.VisibleFrom.Before(*r.VisibleUntil) {
+		return fmt.Errorf("visible_from must be earlier than visible_until")
+	}
+
+	switch r.Type {
+	case RailTypeCategory:
+		if r.CategoryId == nil {
+			return fmt.Errorf("category_id is required for %s rails", r.Type)
+		}
+		r.GenreId, r.MovieIds = nil, nil
+	case RailTypeGenre:
+		if r.GenreId == nil {
+			return fmt.Errorf("genre_id is required for %s rails", r.Type)
+		}
+		r.CategoryId, r.MovieIds = nil, nil
+	case RailTypeHandPicked:
+		if len(r.MovieIds) == 0 {
+			return fmt.Errorf("movie_ids is required for %s rails", r.Type)
+		}
+		seen := make(map[int]bool, len(r.MovieIds))
+		for _, id := range r.MovieIds {
+			if seen[id] {
+				return fmt.Errorf("movie_ids contains duplicate movie %d", id)
+			}
+			seen[id] = true
+		}
+		r.CategoryId, r.GenreId = nil, nil
+	case RailTypeContinueWatching, RailTypeNewReleases:
+		r.CategoryId, r.GenreId, r.MovieIds = nil, nil, nil
+	default:
+		return fmt.Errorf("type must be one of %s, %s, %s, %s, %s", RailTypeCategory, RailTypeGenre,
+			RailTypeHandPicked, RailTypeContinueWatching, RailTypeNewReleases)
+	}
+
+	return nil
+}
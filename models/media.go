@@ -1,6 +1,60 @@
 package models
 
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	maxMediaCaptionLength = 500
+	maxMediaAltTextLength = 300
+)
+
+// MovieMedia — обложка и галерея фильма. Screenshots — ключи (или ссылки) кадров в порядке галереи,
+// Items — те же кадры с подписями; заполняется там, где нужна сама галерея
 type MovieMedia struct {
-    Cover      *string  `json:"cover"`
-    Screenshots []string `json:"screenshots"`
-}
\ No newline at end of file
+	Cover       *string          `json:"cover"`
+	Screenshots []string         `json:"screenshots"`
+	Items       []MovieMediaItem `json:"items,omitempty"`
+}
+
+// MovieMediaItem — кадр галереи. Key — ключ в хранилище, URL — ссылка на него для клиента
+type MovieMediaItem struct {
+	Id       int    `json:"id"`
+	Key      string `json:"key"`
+	URL      string `json:"url"`
+	Position int    `json:"position"`
+	Caption  string `json:"caption"`
+	AltText  string `json:"alt_text"`
+	IsHero   bool   `json:"is_hero"`
+}
+
+// SetItems заполняет галерею и ключи скриншотов в её порядке
+func (m *MovieMedia) SetItems(items []MovieMediaItem) {
+	m.Items = items
+	m.Screenshots = make([]string, 0, len(items))
+	for _, item := range items {
+		m.Screenshots = append(m.Screenshots, item.Key)
+	}
+}
+
+// MovieMediaPatch — изменение кадра галереи; nil-поля не меняются.
+// IsHero=true снимает отметку главного кадра с остальных
+type MovieMediaPatch struct {
+	Caption *string `json:"caption"`
+	AltText *string `json:"alt_text"`
+	IsHero  *bool   `json:"is_hero"`
+}
+
+func (p MovieMediaPatch) Validate() error {
+	if p.Caption == nil && p.AltText == nil && p.IsHero == nil {
+		return fmt.Errorf("nothing to update, expected caption, alt_text or is_hero")
+	}
+	if p.Caption != nil && utf8.RuneCountInString(*p.Caption) > maxMediaCaptionLength {
+		return fmt.Errorf("caption must not exceed %d characters", maxMediaCaptionLength)
+	}
+	if p.AltText != nil && utf8.RuneCountInString(*p.AltText) > maxMediaAltTextLength {
+		return fmt.Errorf("alt_text must not exceed %d characters", maxMediaAltTextLength)
+	}
+	return nil
+}
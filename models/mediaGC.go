@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// MediaGCReport — итог сборки мусора в хранилище медиафайлов. При DryRun ничего не удаляется,
+// а Orphans перечисляет файлы, которые были бы удалены
+type MediaGCReport struct {
+	DryRun      bool            `json:"dry_run"`
+	GracePeriod string          `json:"grace_period"`
+	StartedAt   time.Time       `json:"started_at"`
+	Scanned     int             `json:"scanned"`    // объектов в хранилище
+	Referenced  int             `json:"referenced"` // из них используются фильмами, жанрами или возрастами
+	Recent      int             `json:"recent"`     // не используются, но моложе grace_period
+	Orphans     []OrphanedMedia `json:"orphans"`
+	Deleted     int             `json:"deleted"`
+	FreedBytes  int64           `json:"freed_bytes"`
+	Failed      int             `json:"failed"`
+}
+
+// OrphanedMedia — файл без ссылок из базы. Error заполняется, если удалить его не удалось
+type OrphanedMedia struct {
+	Key        string    `json:"key"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+	Error      string    `json:"error,omitempty"`
+}
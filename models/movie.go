@@ -1,5 +1,12 @@
 package models
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
 type Movie struct {
 	Id			int
 	Title		string
@@ -16,11 +23,137 @@ type Movie struct {
 	MovieTypeId int
 	MovieType	string
 	Media       MovieMedia
+	CreatedAt	time.Time
+	Status		string
+	PublishAt	*time.Time
+	UnpublishAt	*time.Time
+}
+
+// MovieSearchResult — фильм из полнотекстового поиска с релевантностью и подсвеченными фрагментами
+type MovieSearchResult struct {
+	Movie
+	Rank		float64				`json:"rank"`
+	Highlights	MovieHighlights		`json:"highlights"`
+}
+
+// Совпадения обёрнуты в <b></b>
+type MovieHighlights struct {
+	Title		string	`json:"title"`
+	Description	string	`json:"description"`
 }
 
 type Moviesfilters struct {
-	GenreIds	string
-	CategoryIds	string
-	TypeIds		string
-	AgeIds		string
-}
\ No newline at end of file
+	GenreIds		[]int
+	GenreMatch		string	// any или all
+	CategoryIds		[]int
+	CategoryMatch	string	// any или all
+	TypeIds			[]int
+	AgeIds			[]int
+	AgeMatch		string	// any или all
+
+	YearFrom		int		// 0 — без ограничения
+	YearTo			int
+	RuntimeFrom		int
+	RuntimeTo		int
+
+	Director		string	// подстрока без учёта регистра
+	Producer		string
+	Keywords		[]string
+	KeywordMatch	string	// any или all
+
+	Statuses		[]string	// Пустой список — фильмы в любом состоянии
+
+	Sort		string	// title, release_year или created_at
+	Order		string	// asc или desc
+	Limit		int
+	Cursor		string	// next_cursor из предыдущей страницы
+}
+
+const (
+	MatchAny = "any"
+	MatchAll = "all"
+
+	MoviesSortTitle       = "title"
+	MoviesSortReleaseYear = "release_year"
+	MoviesSortCreatedAt   = "created_at"
+
+	DefaultMoviesLimit = 20
+	MaxMoviesLimit     = 100
+)
+
+// MoviesPage — одна страница каталога. NextCursor пустой, если страница последняя
+type MoviesPage struct {
+	Movies		[]Movie	`json:"movies"`
+	Total		int		`json:"total"`
+	NextCursor	string	`json:"next_cursor"`
+}
+
+// Validate проверяет параметры сортировки и пагинации и подставляет значения по умолчанию
+func (f *Moviesfilters) Validate() error {
+	for name, match := range map[string]*string{
+		"genrematch":    &f.GenreMatch,
+		"categorymatch": &f.CategoryMatch,
+		"agematch":      &f.AgeMatch,
+		"keywordmatch":  &f.KeywordMatch,
+	} {
+		switch *match {
+		case "":
+			*match = MatchAny
+		case MatchAny, MatchAll:
+		default:
+			return fmt.Errorf("%s must be %s or %s", name, MatchAny, MatchAll)
+		}
+	}
+
+	for _, status := range f.Statuses {
+		if !slices.Contains(MovieStatuses, status) {
+			return fmt.Errorf("status must be a comma-separated list of %s", strings.Join(MovieStatuses, ", "))
+		}
+	}
+
+	if err := validateRange("year", f.YearFrom, f.YearTo); err != nil {
+		return err
+	}
+	if err := validateRange("runtime", f.RuntimeFrom, f.RuntimeTo); err != nil {
+		return err
+	}
+
+	switch f.Sort {
+	case "":
+		f.Sort = MoviesSortCreatedAt
+	case MoviesSortTitle, MoviesSortReleaseYear, MoviesSortCreatedAt:
+	default:
+		return fmt.Errorf("sort must be one of %s, %s, %s", MoviesSortTitle, MoviesSortReleaseYear, MoviesSortCreatedAt)
+	}
+
+	switch f.Order {
+	case "":
+		// Новинки и свежие фильмы интереснее сверху, алфавит — по возрастанию
+		f.Order = "desc"
+		if f.Sort == MoviesSortTitle {
+			f.Order = "asc"
+		}
+	case "asc", "desc":
+	default:
+		return fmt.Errorf("order must be asc or desc")
+	}
+
+	if f.Limit == 0 {
+		f.Limit = DefaultMoviesLimit
+	}
+	if f.Limit < 1 || f.Limit > MaxMoviesLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxMoviesLimit)
+	}
+
+	return nil
+}
+
+func validateRange(name string, from, to int) error {
+	if from < 0 || to < 0 {
+		return fmt.Errorf("%sfrom and %sto must not be negative", name, name)
+	}
+	if from != 0 && to != 0 && from > to {
+		return fmt.Errorf("%sfrom must not be greater than %sto", name, name)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package models
+
+// MovieCard — облегчённое представление фильма для лент и подборок
+type MovieCard struct {
+	Id			int		`json:"id"`
+	Title		string	`json:"title"`
+	Cover		*string	`json:"cover"`
+	ReleaseYear	int		`json:"release_year"`
+	MovieTypeId	int		`json:"movie_type_id"`
+	MovieType	string	`json:"movie_type"`
+}
+
+// CategoryRail — лента главной страницы с фильмами одной категории
+type CategoryRail struct {
+	Id		int			`json:"id"`
+	Title	string		`json:"title"`
+	Movies	[]MovieCard	`json:"movies"`
+}
+
+// MovieCardsPage — страница карточек для зрителя: без статуса публикации и расписания
+type MovieCardsPage struct {
+	Movies		[]MovieCard	`json:"movies"`
+	Total		int			`json:"total"`
+	NextCursor	string		`json:"next_cursor"`
+}
+
+// MovieCardSearchResult — карточка фильма из поиска с релевантностью и подсвеченными фрагментами
+type MovieCardSearchResult struct {
+	MovieCard
+	Rank		float64			`json:"rank"`
+	Highlights	MovieHighlights	`json:"highlights"`
+}
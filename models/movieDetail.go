@@ -0,0 +1,50 @@
+package models
+
+// NamedRef — справочное значение (жанр, категория, возраст, тип) в публичных ответах
+type NamedRef struct {
+	Id		int		`json:"id"`
+	Title	string	`json:"title"`
+}
+
+// MovieDetail — карточка фильма для зрителя: без статуса публикации и расписания,
+// с отметками просмотра и связанными фильмами
+type MovieDetail struct {
+	Id				int				`json:"id"`
+	Title			string			`json:"title"`
+	Description		string			`json:"description"`
+	ReleaseYear		int				`json:"release_year"`
+	Runtime			int				`json:"runtime"`
+	Keywords		[]string		`json:"keywords"`
+	Director		string			`json:"director"`
+	Producer		string			`json:"producer"`
+	MovieType		NamedRef		`json:"movie_type"`
+	Genres			[]NamedRef		`json:"genres"`
+	Categories		[]NamedRef		`json:"categories"`
+	Ages			[]NamedRef		`json:"ages"`
+	Cover			*string			`json:"cover"`
+	Screenshots		[]string		`json:"screenshots"`
+	Gallery			[]MovieMediaItem	`json:"gallery"`	// скриншоты с подписями, главный кадр отмечен is_hero
+	Assets			[]VideoAsset	`json:"assets"`	// видео фильма без сезонов; у сериала — пустой список
+	Seasons			[]SeasonDetail	`json:"seasons"`
+	InWatchlist		bool			`json:"in_watchlist"`
+	Watched			bool			`json:"watched"`
+	PositionSeconds	int				`json:"position_seconds"`	// только для полнометражных фильмов
+	DurationSeconds	int				`json:"duration_seconds"`
+	Related			[]MovieCard		`json:"related"`
+}
+
+type SeasonDetail struct {
+	Id			int				`json:"id"`
+	Number		int				`json:"number"`
+	Episodes	[]EpisodeDetail	`json:"episodes"`
+}
+
+type EpisodeDetail struct {
+	Id				int		`json:"id"`
+	Number			int		`json:"number"`
+	VideoURL		string	`json:"video_url"`
+	Assets			[]VideoAsset	`json:"assets"`
+	Watched			bool	`json:"watched"`
+	PositionSeconds	int		`json:"position_seconds"`
+	DurationSeconds	int		`json:"duration_seconds"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	MovieStatusDraft     = "draft"
+	MovieStatusScheduled = "scheduled"
+	MovieStatusPublished = "published"
+	MovieStatusArchived  = "archived"
+)
+
+var MovieStatuses = []string{MovieStatusDraft, MovieStatusScheduled, MovieStatusPublished, MovieStatusArchived}
+
+// Допустимые переходы между состояниями. Переход в то же состояние меняет только расписание
+var movieStatusTransitions = map[string][]string{
+	MovieStatusDraft:     {MovieStatusScheduled, MovieStatusPublished, MovieStatusArchived},
+	MovieStatusScheduled: {MovieStatusDraft, MovieStatusScheduled, MovieStatusPublished, MovieStatusArchived},
+	MovieStatusPublished: {MovieStatusDraft, MovieStatusPublished, MovieStatusArchived},
+	MovieStatusArchived:  {MovieStatusDraft, MovieStatusPublished},
+}
+
+// MovieStatusChange — запрошенный перевод фильма в другое состояние
+type MovieStatusChange struct {
+	Status      string
+	PublishAt   *time.Time
+	UnpublishAt *time.Time
+}
+
+// Validate проверяет переход из current и приводит расписание к виду, который нужно сохранить:
+// черновик расписания не имеет, опубликованный фильм получает время публикации, архив хранит только его
+func (s *MovieStatusChange) Validate(current Movie, now time.Time) error {
+	if !slices.Contains(MovieStatuses, s.Status) {
+		return fmt.Errorf("status must be one of %s, %s, %s, %s", MovieStatusDraft, MovieStatusScheduled, MovieStatusPublished, MovieStatusArchived)
+	}
+	if !slices.Contains(movieStatusTransitions[current.Status], s.Status) {
+		return fmt.Errorf("cannot move movie from %s to %s", current.Status, s.Status)
+	}
+
+	switch s.Status {
+	case MovieStatusDraft:
+		s.PublishAt, s.UnpublishAt = nil, nil
+		return nil
+	case MovieStatusArchived:
+		s.PublishAt, s.UnpublishAt = current.PublishAt, nil
+		return nil
+	case MovieStatusScheduled:
+		if s.PublishAt == nil || !s.PublishAt.After(now) {
+			return fmt.Errorf("publish_at in the future is required for scheduled movies")
+		}
+	case MovieStatusPublished:
+		if s.PublishAt != nil && s.PublishAt.After(now) {
+			return fmt.Errorf("publish_at is in the future, use the scheduled status instead")
+		}
+		if s.PublishAt == nil {
+			s.PublishAt = &now
+			if current.Status == MovieStatusPublished && current.PublishAt != nil {
+				s.PublishAt = current.PublishAt
+			}
+		}
+	}
+
+	if s.UnpublishAt != nil && (!s.UnpublishAt.After(now) || !s.UnpublishAt.After(*s.PublishAt)) {
+		return fmt.Errorf("unpublish_at must be in the future and later than publish_at")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package models
+
+type Permission struct {
+	Id          int
+	Name        string
+	Description string
+}
+
+// Названия прав, которые требуют маршруты админки
+const (
+	PermMoviesRead           = "movies:read"
+	PermMoviesWrite          = "movies:write"
+	PermMediaRead            = "media:read"
+	PermMediaWrite           = "media:write"
+	PermRecommendationsRead  = "recommendations:read"
+	PermRecommendationsWrite = "recommendations:write"
+	PermMovieTypesRead       = "movie_types:read"
+	PermMovieTypesWrite      = "movie_types:write"
+	PermCategoriesRead       = "categories:read"
+	PermCategoriesWrite      = "categories:write"
+	PermGenresRead           = "genres:read"
+	PermGenresWrite          = "genres:write"
+	PermAgesRead             = "ages:read"
+	PermAgesWrite            = "ages:write"
+	PermUsersRead            = "users:read"
+	PermUsersWrite           = "users:write"
+	PermRolesRead            = "roles:read"
+	PermRolesWrite           = "roles:write"
+	PermSearchRead           = "search:read"
+	PermHomepageRead         = "homepage:read"
+	PermHomepageWrite        = "homepage:write"
+)
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// WatchedThreshold — доля длительности, после которой фильм или эпизод считается просмотренным
+const WatchedThreshold = 0.9
+
+// PlaybackProgress — позиция просмотра фильма. EpisodeId пуст для полнометражных фильмов
+type PlaybackProgress struct {
+	MovieId			int			`json:"movie_id"`
+	EpisodeId		*int		`json:"episode_id"`
+	PositionSeconds	int			`json:"position_seconds"`
+	DurationSeconds	int			`json:"duration_seconds"`
+	Watched			bool		`json:"watched"`
+	UpdatedAt		time.Time	`json:"updated_at"`
+}
+
+// PlaybackReport — позиция, которую присылает плеер. Watched позволяет отметить просмотр вручную
+type PlaybackReport struct {
+	PositionSeconds	int		`json:"position_seconds"`
+	DurationSeconds	int		`json:"duration_seconds"`
+	Watched			*bool	`json:"watched"`
+}
+
+func (r *PlaybackReport) Validate() error {
+	if r.DurationSeconds <= 0 {
+		return errors.New("duration_seconds must be positive")
+	}
+	if r.PositionSeconds < 0 || r.PositionSeconds > r.DurationSeconds {
+		return errors.New("position_seconds must be between 0 and duration_seconds")
+	}
+	return nil
+}
+
+// IsWatched — явная отметка клиента или позиция дальше порога WatchedThreshold
+func (r *PlaybackReport) IsWatched() bool {
+	if r.Watched != nil {
+		return *r.Watched
+	}
+	return float64(r.PositionSeconds) >= WatchedThreshold*float64(r.DurationSeconds)
+}
+
+// EpisodeRef — эпизод сериала вместе с номером сезона
+type EpisodeRef struct {
+	EpisodeId		int	`json:"episode_id"`
+	EpisodeNumber	int	`json:"episode_number"`
+	SeasonId		int	`json:"season_id"`
+	SeasonNumber	int	`json:"season_number"`
+}
+
+// NextEpisode — эпизод, с которого продолжить сериал. Resume означает, что эпизод досмотрен не до конца
+type NextEpisode struct {
+	EpisodeRef
+	PositionSeconds	int		`json:"position_seconds"`
+	DurationSeconds	int		`json:"duration_seconds"`
+	Resume			bool	`json:"resume"`
+}
+
+// ResolveNextEpisode выбирает, что смотреть дальше. episodes — эпизоды сериала по порядку сезонов и серий,
+// latest — последняя запись прогресса по сериалу. Без прогресса это первый эпизод, недосмотренный эпизод
+// продолжается с места остановки, после просмотренного идёт следующий. nil — сериал досмотрен или эпизодов нет
+func ResolveNextEpisode(episodes []EpisodeRef, latest *PlaybackProgress) *NextEpisode {
+	if len(episodes) == 0 {
+		return nil
+	}
+	if latest == nil || latest.EpisodeId == nil {
+		return &NextEpisode{EpisodeRef: episodes[0]}
+	}
+
+	for i, episode := range episodes {
+		if episode.EpisodeId != *latest.EpisodeId {
+			continue
+		}
+		if !latest.Watched {
+			return &NextEpisode{
+				EpisodeRef:      episode,
+				PositionSeconds: latest.PositionSeconds,
+				DurationSeconds: latest.DurationSeconds,
+				Resume:          true,
+			}
+		}
+		if i+1 < len(episodes) {
+			return &NextEpisode{EpisodeRef: episodes[i+1]}
+		}
+		return nil
+	}
+
+	// Эпизод удалён после просмотра — начинаем с начала
+	return &NextEpisode{EpisodeRef: episodes[0]}
+}
+
+// EpisodeWatchState — отметка просмотра эпизода для карточки фильма
+type EpisodeWatchState struct {
+	EpisodeRef
+	PositionSeconds	int		`json:"position_seconds"`
+	DurationSeconds	int		`json:"duration_seconds"`
+	Watched			bool	`json:"watched"`
+}
+
+// MovieWatchState — отметки просмотра фильма пользователем. Сериал просмотрен, когда просмотрены все эпизоды
+type MovieWatchState struct {
+	MovieId			int					`json:"movie_id"`
+	Watched			bool				`json:"watched"`
+	PositionSeconds	int					`json:"position_seconds"`
+	DurationSeconds	int					`json:"duration_seconds"`
+	Episodes		[]EpisodeWatchState	`json:"episodes"`
+}
+
+// NewMovieWatchState собирает отметки по эпизодам в порядке episodes; для фильма без эпизодов берётся запись без EpisodeId
+func NewMovieWatchState(movieId int, episodes []EpisodeRef, progress []PlaybackProgress) MovieWatchState {
+	state := MovieWatchState{MovieId: movieId, Episodes: make([]EpisodeWatchState, 0, len(episodes))}
+
+	byEpisode := make(map[int]PlaybackProgress, len(progress))
+	for _, p := range progress {
+		if p.EpisodeId == nil {
+			if len(episodes) == 0 {
+				state.Watched = p.Watched
+				state.PositionSeconds = p.PositionSeconds
+				state.DurationSeconds = p.DurationSeconds
+			}
+			continue
+		}
+		byEpisode[*p.EpisodeId] = p
+	}
+	if len(episodes) == 0 {
+		return state
+	}
+
+	state.Watched = true
+	for _, episode := range episodes {
+		p := byEpisode[episode.EpisodeId]
+		state.Episodes = append(state.Episodes, EpisodeWatchState{
+			EpisodeRef:      episode,
+			PositionSeconds: p.PositionSeconds,
+			DurationSeconds: p.DurationSeconds,
+			Watched:         p.Watched,
+		})
+		state.Watched = state.Watched && p.Watched
+	}
+	return state
+}
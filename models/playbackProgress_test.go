@@ -0,0 +1,142 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testEpisodes() []EpisodeRef {
+	return []EpisodeRef{
+		{EpisodeId: 11, EpisodeNumber: 1, SeasonId: 1, SeasonNumber: 1},
+		{EpisodeId: 12, EpisodeNumber: 2, SeasonId: 1, SeasonNumber: 1},
+		{EpisodeId: 21, EpisodeNumber: 1, SeasonId: 2, SeasonNumber: 2},
+	}
+}
+
+func episodeProgress(episodeId, position int, watched bool) PlaybackProgress {
+	return PlaybackProgress{MovieId: 1, EpisodeId: &episodeId, PositionSeconds: position, DurationSeconds: 1500, Watched: watched}
+}
+
+func TestResolveNextEpisode(t *testing.T) {
+	episodes := testEpisodes()
+	unfinished := episodeProgress(12, 600, false)
+	watchedFirst := episodeProgress(11, 1450, true)
+	watchedSeasonEnd := episodeProgress(12, 1500, true)
+	watchedLast := episodeProgress(21, 1500, true)
+	deleted := episodeProgress(99, 300, false)
+	filmRow := PlaybackProgress{MovieId: 1, PositionSeconds: 300, DurationSeconds: 5400}
+
+	cases := []struct {
+		name     string
+		episodes []EpisodeRef
+		latest   *PlaybackProgress
+		want     *NextEpisode
+	}{
+		{"no progress starts from the first episode", episodes, nil, &NextEpisode{EpisodeRef: episodes[0]}},
+		{"unfinished episode resumes", episodes, &unfinished,
+			&NextEpisode{EpisodeRef: episodes[1], PositionSeconds: 600, DurationSeconds: 1500, Resume: true}},
+		{"watched episode moves to the next one", episodes, &watchedFirst, &NextEpisode{EpisodeRef: episodes[1]}},
+		{"last episode of a season moves to the next season", episodes, &watchedSeasonEnd, &NextEpisode{EpisodeRef: episodes[2]}},
+		{"watched last episode finishes the series", episodes, &watchedLast, nil},
+		{"deleted episode starts over", episodes, &deleted, &NextEpisode{EpisodeRef: episodes[0]}},
+		{"film row inside a series starts from the first episode", episodes, &filmRow, &NextEpisode{EpisodeRef: episodes[0]}},
+		{"movie without episodes", nil, &unfinished, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ResolveNextEpisode(tc.episodes, tc.latest)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ResolveNextEpisode() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewMovieWatchState(t *testing.T) {
+	episodes := testEpisodes()
+	filmRow := PlaybackProgress{MovieId: 1, PositionSeconds: 5000, DurationSeconds: 5400, Watched: true}
+
+	cases := []struct {
+		name          string
+		episodes      []EpisodeRef
+		progress      []PlaybackProgress
+		wantWatched   bool
+		wantPosition  int
+		wantEpisodes  []bool
+		wantPositions []int
+	}{
+		{
+			name:          "series without progress",
+			episodes:      episodes,
+			wantEpisodes:  []bool{false, false, false},
+			wantPositions: []int{0, 0, 0},
+		},
+		{
+			name:          "unfinished episode",
+			episodes:      episodes,
+			progress:      []PlaybackProgress{episodeProgress(12, 600, false), episodeProgress(11, 1500, true)},
+			wantEpisodes:  []bool{true, false, false},
+			wantPositions: []int{1500, 600, 0},
+		},
+		{
+			name:     "all episodes watched",
+			episodes: episodes,
+			progress: []PlaybackProgress{
+				episodeProgress(21, 1500, true), episodeProgress(12, 1500, true), episodeProgress(11, 1500, true),
+			},
+			wantWatched:   true,
+			wantEpisodes:  []bool{true, true, true},
+			wantPositions: []int{1500, 1500, 1500},
+		},
+		{
+			name:     "progress of a deleted episode is ignored",
+			episodes: episodes[:2],
+			progress: []PlaybackProgress{
+				episodeProgress(21, 300, false), episodeProgress(12, 1500, true), episodeProgress(11, 1500, true),
+			},
+			wantWatched:   true,
+			wantEpisodes:  []bool{true, true},
+			wantPositions: []int{1500, 1500},
+		},
+		{
+			name:          "film row inside a series is ignored",
+			episodes:      episodes,
+			progress:      []PlaybackProgress{filmRow, episodeProgress(11, 1500, true)},
+			wantEpisodes:  []bool{true, false, false},
+			wantPositions: []int{1500, 0, 0},
+		},
+		{
+			name:         "film uses its own row",
+			progress:     []PlaybackProgress{filmRow},
+			wantWatched:  true,
+			wantPosition: 5000,
+		},
+		{
+			name: "film without progress",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := NewMovieWatchState(1, tc.episodes, tc.progress)
+
+			if state.MovieId != 1 || state.Watched != tc.wantWatched || state.PositionSeconds != tc.wantPosition {
+				t.Errorf("state = {movie %d, watched %v, position %d}, want {movie 1, watched %v, position %d}",
+					state.MovieId, state.Watched, state.PositionSeconds, tc.wantWatched, tc.wantPosition)
+			}
+			if len(state.Episodes) != len(tc.wantEpisodes) {
+				t.Fatalf("len(Episodes) = %d, want %d", len(state.Episodes), len(tc.wantEpisodes))
+			}
+			for i, episode := range state.Episodes {
+				if episode.EpisodeRef != tc.episodes[i] {
+					t.Errorf("Episodes[%d] = %+v, want %+v", i, episode.EpisodeRef, tc.episodes[i])
+				}
+				if episode.Watched != tc.wantEpisodes[i] || episode.PositionSeconds != tc.wantPositions[i] {
+					t.Errorf("Episodes[%d] = {watched %v, position %d}, want {watched %v, position %d}",
+						i, episode.Watched, episode.PositionSeconds, tc.wantEpisodes[i], tc.wantPositions[i])
+				}
+			}
+		})
+	}
+}
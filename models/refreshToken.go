@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type RefreshToken struct {
+	Id        int
+	UserId    int
+	FamilyId  string    // Все токены, полученные ротацией от одного входа, имеют общий FamilyId
+	TokenHash string    // В базе хранится только SHA-256 от самого токена
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
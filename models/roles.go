@@ -3,10 +3,5 @@ package models
 type Role struct {
 	Id    				int
 	Name  				string
-	CanEditProjects   	bool
-	CanEditCategories 	bool
-	CanEditUsers      	bool
-	CanEditRoles      	bool
-	CanEditGenres     	bool
-	CanEditAges      	bool
-}
\ No newline at end of file
+	Permissions 		[]string	// Названия прав, например "movies:write"
+}
@@ -0,0 +1,16 @@
+package models
+
+const (
+	SuggestionTitle    = "title"
+	SuggestionKeyword  = "keyword"
+	SuggestionPerson   = "person"
+	SuggestionCategory = "category"
+	SuggestionGenre    = "genre"
+)
+
+// Suggestion — вариант автодополнения. Id заполнен для фильмов, категорий и жанров
+type Suggestion struct {
+	Type	string	`json:"type"`
+	Id		int		`json:"id,omitempty"`
+	Text	string	`json:"text"`
+}
@@ -0,0 +1,49 @@
+package models
+
+// Виды загружаемых изображений, от которых зависят ограничения
+const (
+	ImageKindCover      = "cover"
+	ImageKindScreenshot = "screenshot"
+	ImageKindPoster     = "poster"
+)
+
+// Коды причин отказа в загрузке
+const (
+	UploadErrFileTooLarge      = "file_too_large"
+	UploadErrUnsupportedType   = "unsupported_type"
+	UploadErrInvalidImage      = "invalid_image"
+	UploadErrInvalidDimensions = "invalid_dimensions"
+)
+
+// ImageRule — ограничения на размер файла в байтах и размеры изображения в пикселях
+type ImageRule struct {
+	MaxBytes  int64 `json:"max_bytes"`
+	MinWidth  int   `json:"min_width"`
+	MinHeight int   `json:"min_height"`
+	MaxWidth  int   `json:"max_width"`
+	MaxHeight int   `json:"max_height"`
+}
+
+// ImageRules — ограничения по видам изображений. Обложка вертикальная, скриншот — кадр не меньше 640x360
+var ImageRules = map[string]ImageRule{
+	ImageKindCover:      {MaxBytes: 5 << 20, MinWidth: 300, MinHeight: 450, MaxWidth: 4000, MaxHeight: 6000},
+	ImageKindScreenshot: {MaxBytes: 10 << 20, MinWidth: 640, MinHeight: 360, MaxWidth: 7680, MaxHeight: 4320},
+	ImageKindPoster:     {MaxBytes: 5 << 20, MinWidth: 200, MinHeight: 200, MaxWidth: 4000, MaxHeight: 4000},
+}
+
+// FitsDimensions проверяет размеры изображения; у обложки высота не меньше ширины
+func (r ImageRule) FitsDimensions(kind string, width, height int) bool {
+	if width < r.MinWidth || height < r.MinHeight || width > r.MaxWidth || height > r.MaxHeight {
+		return false
+	}
+	return kind != ImageKindCover || height >= width
+}
+
+// UploadError — ответ на отклонённую загрузку: код причины, поле формы, имя файла и нарушенные ограничения
+type UploadError struct {
+	ApiError
+	Code     string     `json:"code"`
+	Field    string     `json:"field,omitempty"`
+	Filename string     `json:"filename,omitempty"`
+	Rule     *ImageRule `json:"rule,omitempty"`
+}
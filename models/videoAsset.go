@@ -0,0 +1,214 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	VideoKindMain    = "main"
+	VideoKindTrailer = "trailer"
+
+	SubtitleFormatVTT = "vtt"
+	SubtitleFormatSRT = "srt"
+)
+
+// VideoKinds — основное видео одно на эпизод или фильм, трейлеров может быть несколько
+var VideoKinds = []string{VideoKindMain, VideoKindTrailer}
+
+// SubtitleLanguages — языки, на которых выпускаются субтитры
+var SubtitleLanguages = []string{"kk", "ru", "en"}
+
+// audioLanguagePattern — код языка аудиодорожки по ISO 639 с необязательным регионом: kk, tur, en-US.
+// Список языков не ограничен: дорожки приходят с озвучкой и в оригинале
+var audioLanguagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// VideoQualities — допустимые названия качеств от худшего к лучшему
+var VideoQualities = []string{"240p", "360p", "480p", "720p", "1080p", "1440p", "2160p"}
+
+var subtitleFormats = []string{SubtitleFormatVTT, SubtitleFormatSRT}
+
+// VideoOwner — эпизод или полнометражный фильм без сезонов, к которому привязано видео.
+// Задан ровно один из EpisodeId и MovieId
+type VideoOwner struct {
+	EpisodeId	*int	`json:"episode_id,omitempty"`
+	MovieId		*int	`json:"movie_id,omitempty"`
+}
+
+func EpisodeVideoOwner(episodeId int) VideoOwner {
+	return VideoOwner{EpisodeId: &episodeId}
+}
+
+func MovieVideoOwner(movieId int) VideoOwner {
+	return VideoOwner{MovieId: &movieId}
+}
+
+// VideoAsset — видео эпизода или фильма. Плеер выбирает HLS или DASH манифест,
+// Renditions описывают качества внутри манифеста
+type VideoAsset struct {
+	Id				int					`json:"id"`
+	VideoOwner
+	Kind			string				`json:"kind"`
+	DurationSeconds	int					`json:"duration_seconds"`
+	HlsURL			string				`json:"hls_url"`
+	DashURL			string				`json:"dash_url"`
+	Renditions		[]VideoRendition	`json:"renditions"`
+	Subtitles		[]SubtitleTrack		`json:"subtitles"`
+	AudioTracks		[]AudioTrack		`json:"audio_tracks"`
+	UpdatedAt		time.Time			`json:"updated_at"`
+}
+
+// VideoRendition — одно качество видео. URL — плейлист качества, если плеер выбирает его сам
+type VideoRendition struct {
+	Quality		string	`json:"quality"`
+	Width		int		`json:"width"`
+	Height		int		`json:"height"`
+	BitrateKbps	int		`json:"bitrate_kbps"`
+	Codec		string	`json:"codec"`
+	URL			string	`json:"url"`
+}
+
+type SubtitleTrack struct {
+	Language	string	`json:"language"`	// kk, ru или en
+	Label		string	`json:"label"`
+	Format		string	`json:"format"`	// vtt или srt
+	URL			string	`json:"url"`
+	IsDefault	bool	`json:"is_default"`
+}
+
+type AudioTrack struct {
+	Language	string	`json:"language"`	// код ISO 639: kk, ru, tr, en-US
+	Label		string	`json:"label"`
+	Codec		string	`json:"codec"`
+	Channels	int		`json:"channels"`
+	IsDefault	bool	`json:"is_default"`
+}
+
+// Validate проверяет видео и его дорожки и подставляет значения по умолчанию:
+// вид main, формат субтитров vtt, стерео для аудио
+func (a *VideoAsset) Validate() error {
+	if a.Kind == "" {
+		a.Kind = VideoKindMain
+	}
+	if !slices.Contains(VideoKinds, a.Kind) {
+		return fmt.Errorf("kind must be one of %s", strings.Join(VideoKinds, ", "))
+	}
+
+	if a.DurationSeconds <= 0 {
+		return fmt.Errorf("duration_seconds must be positive")
+	}
+
+	if a.HlsURL == "" && a.DashURL == "" {
+		return fmt.Errorf("hls_url or dash_url is required")
+	}
+	for name, value := range map[string]string{"hls_url": a.HlsURL, "dash_url": a.DashURL} {
+		if err := validateVideoURL(name, value); err != nil {
+			return err
+		}
+	}
+
+	if a.Renditions == nil {
+		a.Renditions = []VideoRendition{}
+	}
+	qualities := make(map[string]bool, len(a.Renditions))
+	for _, r := range a.Renditions {
+		if !slices.Contains(VideoQualities, r.Quality) {
+			return fmt.Errorf("rendition quality must be one of %s", strings.Join(VideoQualities, ", "))
+		}
+		if qualities[r.Quality] {
+			return fmt.Errorf("renditions contain duplicate quality %s", r.Quality)
+		}
+		qualities[r.Quality] = true
+
+		if r.Width <= 0 || r.Height <= 0 || r.BitrateKbps <= 0 {
+			return fmt.Errorf("rendition %s must have positive width, height and bitrate_kbps", r.Quality)
+		}
+		if err := validateVideoURL("rendition url", r.URL); err != nil {
+			return err
+		}
+	}
+
+	if a.Subtitles == nil {
+		a.Subtitles = []SubtitleTrack{}
+	}
+	languages := make(map[string]bool, len(a.Subtitles))
+	defaults := 0
+	for i := range a.Subtitles {
+		s := &a.Subtitles[i]
+		if !slices.Contains(SubtitleLanguages, s.Language) {
+			return fmt.Errorf("subtitle language must be one of %s", strings.Join(SubtitleLanguages, ", "))
+		}
+		if languages[s.Language] {
+			return fmt.Errorf("subtitles contain duplicate language %s", s.Language)
+		}
+		languages[s.Language] = true
+
+		if s.Format == "" {
+			s.Format = SubtitleFormatVTT
+		}
+		if !slices.Contains(subtitleFormats, s.Format) {
+			return fmt.Errorf("subtitle format must be one of %s", strings.Join(subtitleFormats, ", "))
+		}
+		if s.URL == "" {
+			return fmt.Errorf("subtitle %s url is required", s.Language)
+		}
+		if err := validateVideoURL("subtitle url", s.URL); err != nil {
+			return err
+		}
+		if s.IsDefault {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return fmt.Errorf("at most one subtitle track can be default")
+	}
+
+	if a.AudioTracks == nil {
+		a.AudioTracks = []AudioTrack{}
+	}
+	// На одном языке может быть несколько дорожек (дубляж, оригинал, комментарии), их различает подпись
+	tracks := make(map[[2]string]bool, len(a.AudioTracks))
+	defaults = 0
+	for i := range a.AudioTracks {
+		t := &a.AudioTracks[i]
+		if !audioLanguagePattern.MatchString(t.Language) {
+			return fmt.Errorf("audio track language must be an ISO 639 code such as kk, ru or en-US")
+		}
+		key := [2]string{t.Language, t.Label}
+		if tracks[key] {
+			return fmt.Errorf("audio_tracks contain duplicate language %s with label %q", t.Language, t.Label)
+		}
+		tracks[key] = true
+
+		if t.Channels == 0 {
+			t.Channels = 2
+		}
+		if t.Channels < 0 {
+			return fmt.Errorf("audio track %s channels must be positive", t.Language)
+		}
+		if t.IsDefault {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return fmt.Errorf("at most one audio track can be default")
+	}
+
+	return nil
+}
+
+// validateVideoURL пропускает пустую строку и абсолютные http(s)-ссылки
+func validateVideoURL(name, value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an absolute http or https URL", name)
+	}
+	return nil
+}
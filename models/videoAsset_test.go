@@ -0,0 +1,51 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVideoAssetAudioTracks(t *testing.T) {
+	cases := []struct {
+		name    string
+		tracks  []AudioTrack
+		wantErr string
+	}{
+		{"languages beyond subtitles", []AudioTrack{{Language: "tr"}, {Language: "en-US"}, {Language: "kaz"}}, ""},
+		{"same language with different labels", []AudioTrack{{Language: "ru", Label: "Дубляж"}, {Language: "ru", Label: "Оригинал"}}, ""},
+		{"same language and label", []AudioTrack{{Language: "ru"}, {Language: "ru"}}, "duplicate language ru"},
+		{"invalid language code", []AudioTrack{{Language: "Russian"}}, "ISO 639"},
+		{"two default tracks", []AudioTrack{{Language: "kk", IsDefault: true}, {Language: "ru", IsDefault: true}}, "at most one audio track"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			asset := VideoAsset{DurationSeconds: 1500, HlsURL: "https://cdn.example.com/master.m3u8", AudioTracks: tc.tracks}
+			err := asset.Validate()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				for _, track := range asset.AudioTracks {
+					if track.Channels != 2 {
+						t.Errorf("channels of %s = %d, want 2", track.Language, track.Channels)
+					}
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	// Субтитры по-прежнему ограничены kk, ru и en
+	asset := VideoAsset{
+		DurationSeconds: 1500,
+		HlsURL:          "https://cdn.example.com/master.m3u8",
+		Subtitles:       []SubtitleTrack{{Language: "tr", URL: "https://cdn.example.com/tr.vtt"}},
+	}
+	if err := asset.Validate(); err == nil {
+		t.Errorf("Validate() accepted a tr subtitle track")
+	}
+}
@@ -16,7 +16,7 @@ func NewAgesRepository(conn *pgxpool.Pool) *AgesRepository {
 }
 
 func (r *AgesRepository) FindAll(c context.Context) ([]models.Ages, error)  {
-	rows, err := r.db.Query(c, "select id, title, poster_url from ages")
+	rows, err := r.db.Query(c, "select id, title, poster_url from ages order by id")
 	if err != nil {
 		return nil, err
 	}
@@ -16,7 +16,7 @@ func NewCategoriesRepository(conn *pgxpool.Pool) *CategoriesRepository {
 }
 
 func (r *CategoriesRepository) FindAll(c context.Context) ([]models.Category, error) {
-	rows, err := r.db.Query(c, "select id, title from categories")
+	rows, err := r.db.Query(c, "select id, title from categories order by id")
 	if err != nil {
 		return nil, err
 	}
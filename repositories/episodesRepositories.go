@@ -75,6 +75,25 @@ func (r *EpisodesRepository) FindAllBySeasonID(c context.Context, seasonID int)
 	return episodes, rows.Err()
 }
 
+// Получение эпизодов нескольких сезонов одним запросом: по сезонам, внутри сезона по порядку номеров
+func (r *EpisodesRepository) FindAllBySeasonIDs(c context.Context, seasonIDs []int) ([]models.Episode, error) {
+	rows, err := r.db.Query(c, `SELECT id, season_id, number, video_url FROM episodes WHERE season_id = ANY($1) ORDER BY season_id, number, id`, seasonIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch episodes: %w", err)
+	}
+	defer rows.Close()
+
+	var episodes []models.Episode
+	for rows.Next() {
+		var episode models.Episode
+		if err := rows.Scan(&episode.Id, &episode.SeasonID, &episode.Number, &episode.VideoURL); err != nil {
+			return nil, fmt.Errorf("failed to scan episode: %w", err)
+		}
+		episodes = append(episodes, episode)
+	}
+	return episodes, rows.Err()
+}
+
 // Обновление эпизода
 func (r *EpisodesRepository) Update(c context.Context, episode models.Episode) error {
 	_, err := r.db.Exec(c, `UPDATE episodes SET number = $1, video_url = $2 WHERE id = $3`,
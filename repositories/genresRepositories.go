@@ -16,7 +16,7 @@ func NewGenresRepository(conn *pgxpool.Pool) *GenresRepository {
 }
 
 func (r *GenresRepository) FindAll(c context.Context) ([]models.Genre, error) {
-	rows, err := r.db.Query(c, "select id, title, poster_url from genres")
+	rows, err := r.db.Query(c, "select id, title, poster_url from genres order by id")
 	if err != nil {
 		return nil, err
 	}
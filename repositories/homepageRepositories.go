@@ -2,8 +2,12 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"ozinshe_production/models"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -15,34 +19,363 @@ func NewHomepageRepository(conn *pgxpool.Pool) *HomepageRepository {
 	return &HomepageRepository{db: conn}
 }
 
-func (r *HomepageRepository) GetRecommendedMovies(c context.Context) ([]models.Movie, error) {
+// Поля карточки фильма; m — movies, mt — movie_types
+const movieCardColumns = `m.id, m.title, m.cover, m.release_year, m.movie_type_id, COALESCE(mt.title, '')`
+
+func scanMovieCard(row pgx.Row, card *models.MovieCard, extra ...any) error {
+	dest := append(extra, &card.Id, &card.Title, &card.Cover, &card.ReleaseYear, &card.MovieTypeId, &card.MovieType)
+	return row.Scan(dest...)
+}
+
+// GetRecommendedCards возвращает до limit рекомендованных фильмов в порядке, заданном в админке
+func (r *HomepageRepository) GetRecommendedCards(c context.Context, limit int) ([]models.MovieCard, error) {
 	rows, err := r.db.Query(c, `
-        SELECT 
-            m.id, m.title, m.release_year, m.runtime, 
-            m.keywords, m.description, m.director, 
-            m.producer, m.cover, m.screenshots, m.movie_type_id
-        FROM recommended_movies rm
-        JOIN movies m ON rm.movie_id = m.id
-        ORDER BY rm.position
-    `)
+		SELECT `+movieCardColumns+`
+		FROM recommended_movies rm
+		JOIN movies m ON rm.movie_id = m.id
+		LEFT JOIN movie_types mt ON mt.id = m.movie_type_id
+		WHERE m.status = 'published'
+		ORDER BY rm.position, rm.id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cards := make([]models.MovieCard, 0)
+	for rows.Next() {
+		var card models.MovieCard
+		if err := scanMovieCard(rows, &card); err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, rows.Err()
+}
+
+// GetCategoryRails одним запросом собирает ленты по всем категориям: до perCategory
+// самых новых фильмов в каждой. Категории без фильмов не попадают в результат
+func (r *HomepageRepository) GetCategoryRails(c context.Context, perCategory int) ([]models.CategoryRail, error) {
+	rows, err := r.db.Query(c, `
+		SELECT c.id, c.title, `+movieCardColumns+`
+		FROM categories c
+		JOIN LATERAL (
+			SELECT m.*
+			FROM movie_categories mc
+			JOIN movies m ON m.id = mc.movie_id
+			WHERE mc.category_id = c.id AND m.status = 'published'
+			ORDER BY m.created_at DESC, m.id DESC
+			LIMIT $1
+		) m ON TRUE
+		LEFT JOIN movie_types mt ON mt.id = m.movie_type_id
+		ORDER BY c.id, m.created_at DESC, m.id DESC
+	`, perCategory)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rails := make([]models.CategoryRail, 0)
+	for rows.Next() {
+		var categoryId int
+		var categoryTitle string
+		var card models.MovieCard
+		if err := scanMovieCard(rows, &card, &categoryId, &categoryTitle); err != nil {
+			return nil, err
+		}
+
+		// Строки отсортированы по категории, поэтому новая лента начинается при смене id
+		if len(rails) == 0 || rails[len(rails)-1].Id != categoryId {
+			rails = append(rails, models.CategoryRail{Id: categoryId, Title: categoryTitle})
+		}
+		rails[len(rails)-1].Movies = append(rails[len(rails)-1].Movies, card)
+	}
+	return rails, rows.Err()
+}
+
+// ErrRailsOrderMismatch возвращается, если новый порядок перечисляет не все ленты или содержит чужие id
+var ErrRailsOrderMismatch = errors.New("rail_ids must list every homepage rail exactly once")
+
+// ErrRailReferenceNotFound возвращается, если лента ссылается на несуществующую категорию, жанр или фильм
+var ErrRailReferenceNotFound = errors.New("category, genre or movie referenced by the rail does not exist")
+
+func railWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return ErrRailReferenceNotFound
+	}
+	return err
+}
+
+const selectRailsSql = `
+	SELECT r.id, r.type, r.title, r.position, r.size, r.category_id, r.genre_id,
+	COALESCE((SELECT array_agg(rm.movie_id ORDER BY rm.position) FROM homepage_rail_movies rm WHERE rm.rail_id = r.id), '{}'),
+	r.visible_from, r.visible_until
+	FROM homepage_rails r
+`
+
+func scanRail(row pgx.Row) (models.HomepageRail, error) {
+	var rail models.HomepageRail
+	err := row.Scan(&rail.Id, &rail.Type, &rail.Title, &rail.Position, &rail.Size, &rail.CategoryId, &rail.GenreId,
+		&rail.MovieIds, &rail.VisibleFrom, &rail.VisibleUntil)
+	return rail, err
+}
+
+func (r *HomepageRepository) findRails(c context.Context, where string) ([]models.HomepageRail, error) {
+	rows, err := r.db.Query(c, selectRailsSql+where+" ORDER BY r.position, r.id")
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var movies []models.Movie
+	rails := make([]models.HomepageRail, 0)
 	for rows.Next() {
-		var movie models.Movie
-		if err := rows.Scan(
-			&movie.Id, &movie.Title, &movie.ReleaseYear, &movie.Runtime,
-			&movie.KeyWords, &movie.Description, &movie.Director,
-			&movie.Producer, &movie.Media.Cover, &movie.Media.Screenshots, &movie.MovieTypeId,
-		); err != nil {
+		rail, err := scanRail(rows)
+		if err != nil {
 			return nil, err
 		}
-		movies = append(movies, movie)
+		rails = append(rails, rail)
+	}
+	return rails, rows.Err()
+}
+
+// FindAllRails возвращает все ленты, включая скрытые по окну видимости
+func (r *HomepageRepository) FindAllRails(c context.Context) ([]models.HomepageRail, error) {
+	return r.findRails(c, "")
+}
+
+// FindVisibleRails возвращает ленты, которые должны отображаться прямо сейчас
+func (r *HomepageRepository) FindVisibleRails(c context.Context) ([]models.HomepageRail, error) {
+	return r.findRails(c, `
+		WHERE (r.visible_from IS NULL OR r.visible_from <= NOW())
+		AND (r.visible_until IS NULL OR r.visible_until > NOW())`)
+}
+
+func (r *HomepageRepository) FindRailById(c context.Context, id int) (models.HomepageRail, error) {
+	return scanRail(r.db.QueryRow(c, selectRailsSql+" WHERE r.id = $1", id))
+}
+
+// CreateRail добавляет ленту в конец главной страницы
+func (r *HomepageRepository) CreateRail(c context.Context, rail models.HomepageRail) (int, error) {
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(c)
+
+	var id int
+	err = tx.QueryRow(c, `
+		INSERT INTO homepage_rails (type, title, position, size, category_id, genre_id, visible_from, visible_until)
+		VALUES ($1, $2, (SELECT COALESCE(MAX(position), 0) + 1 FROM homepage_rails), $3, $4, $5, $6, $7)
+		RETURNING id
+	`, rail.Type, rail.Title, rail.Size, rail.CategoryId, rail.GenreId, rail.VisibleFrom, rail.VisibleUntil).Scan(&id)
+	if err != nil {
+		return 0, railWriteError(err)
+	}
+
+	if err := setRailMovies(c, tx, id, rail.MovieIds); err != nil {
+		return 0, railWriteError(err)
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// UpdateRail меняет настройки ленты, не трогая её позицию
+func (r *HomepageRepository) UpdateRail(c context.Context, id int, rail models.HomepageRail) error {
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(c)
+
+	tag, err := tx.Exec(c, `
+		UPDATE homepage_rails
+		SET type = $1, title = $2, size = $3, category_id = $4, genre_id = $5, visible_from = $6, visible_until = $7
+		WHERE id = $8
+	`, rail.Type, rail.Title, rail.Size, rail.CategoryId, rail.GenreId, rail.VisibleFrom, rail.VisibleUntil, id)
+	if err != nil {
+		return railWriteError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	if err := setRailMovies(c, tx, id, rail.MovieIds); err != nil {
+		return railWriteError(err)
+	}
+
+	return tx.Commit(c)
+}
+
+func (r *HomepageRepository) DeleteRail(c context.Context, id int) error {
+	_, err := r.db.Exec(c, "DELETE FROM homepage_rails WHERE id = $1", id)
+	return err
+}
+
+// ReorderRails выставляет позиции лент в порядке railIds
+func (r *HomepageRepository) ReorderRails(c context.Context, railIds []int) error {
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(c)
+
+	var total int
+	if err := tx.QueryRow(c, "SELECT COUNT(*) FROM homepage_rails").Scan(&total); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(c, `
+		UPDATE homepage_rails r SET position = t.ord
+		FROM unnest($1::INT[]) WITH ORDINALITY AS t(id, ord)
+		WHERE r.id = t.id
+	`, railIds)
+	if err != nil {
+		return err
+	}
+	if int(tag.RowsAffected()) != len(railIds) || len(railIds) != total {
+		return ErrRailsOrderMismatch
+	}
+
+	return tx.Commit(c)
+}
+
+// Заменяет фильмы ленты hand_picked
+func setRailMovies(c context.Context, tx pgx.Tx, railId int, movieIds []int) error {
+	_, err := tx.Exec(c, "DELETE FROM homepage_rail_movies WHERE rail_id = $1", railId)
+	if err != nil {
+		return err
 	}
-	return movies, nil
+
+	_, err = tx.Exec(c, `
+		INSERT INTO homepage_rail_movies (rail_id, movie_id, position)
+		SELECT $1, t.movie_id, t.ord
+		FROM unnest($2::INT[]) WITH ORDINALITY AS t(movie_id, ord)
+	`, railId, movieIds)
+	return err
 }
 
+// FindRailMovies заполняет ленты одного типа одним запросом и возвращает карточки по id ленты.
+// userId нужен лентам, зависящим от зрителя
+func (r *HomepageRepository) FindRailMovies(c context.Context, railType string, rails []models.HomepageRail, userId int) (map[int][]models.MovieCard, error) {
+	cards := make(map[int][]models.MovieCard, len(rails))
+	if len(rails) == 0 {
+		return cards, nil
+	}
+
+	railIds := make([]int, 0, len(rails))
+	refIds := make([]int, 0, len(rails))
+	sizes := make([]int, 0, len(rails))
+	maxSize := 0
+	for _, rail := range rails {
+		railIds = append(railIds, rail.Id)
+		sizes = append(sizes, rail.Size)
+		maxSize = max(maxSize, rail.Size)
+		switch {
+		case rail.CategoryId != nil:
+			refIds = append(refIds, *rail.CategoryId)
+		case rail.GenreId != nil:
+			refIds = append(refIds, *rail.GenreId)
+		default:
+			refIds = append(refIds, 0)
+		}
+	}
+
+	var sql string
+	var args []any
+	switch railType {
+	case models.RailTypeCategory, models.RailTypeGenre:
+		link := "SELECT mc.movie_id FROM movie_categories mc WHERE mc.category_id = r.ref_id"
+		if railType == models.RailTypeGenre {
+			link = "SELECT mg.movie_id FROM movie_genres mg WHERE mg.genre_id = r.ref_id"
+		}
+		sql = `
+			SELECT r.rail_id, ` + movieCardColumns + `
+			FROM unnest($1::INT[], $2::INT[], $3::INT[]) AS r(rail_id, ref_id, size)
+			JOIN LATERAL (
+				SELECT m.* FROM movies m
+				WHERE m.id IN (` + link + `) AND m.status = 'published'
+				ORDER BY m.created_at DESC, m.id DESC
+				LIMIT r.size
+			) m ON TRUE
+			LEFT JOIN movie_types mt ON mt.id = m.movie_type_id
+			ORDER BY r.rail_id, m.created_at DESC, m.id DESC`
+		args = []any{railIds, refIds, sizes}
+	case models.RailTypeHandPicked:
+		sql = `
+			SELECT rm.rail_id, ` + movieCardColumns + `
+			FROM homepage_rail_movies rm
+			JOIN movies m ON m.id = rm.movie_id
+			LEFT JOIN movie_types mt ON mt.id = m.movie_type_id
+			WHERE rm.rail_id = ANY($1) AND m.status = 'published'
+			ORDER BY rm.rail_id, rm.position, m.id`
+		args = []any{railIds}
+	case models.RailTypeNewReleases:
+		// Все ленты новинок одинаковы и отличаются только размером, поэтому запрос один
+		sql = `
+			SELECT 0, ` + movieCardColumns + `
+			FROM movies m
+			LEFT JOIN movie_types mt ON mt.id = m.movie_type_id
+			WHERE m.status = 'published'
+			ORDER BY m.release_year DESC, m.created_at DESC, m.id DESC
+			LIMIT $1`
+		args = []any{maxSize}
+	case models.RailTypeContinueWatching:
+		// Лента своя у каждого зрителя, но все ленты этого типа у него одинаковы
+		sql = `
+			SELECT 0, ` + movieCardColumns + `
+			FROM (` + unfinishedProgressSql + `) p
+			JOIN movies m ON m.id = p.movie_id
+			LEFT JOIN movie_types mt ON mt.id = m.movie_type_id
+			WHERE m.status = 'published'
+			ORDER BY p.updated_at DESC, p.id DESC
+			LIMIT $2`
+		args = []any{userId, maxSize}
+	default:
+		return nil, fmt.Errorf("unknown rail type %q", railType)
+	}
+
+	rows, err := r.db.Query(c, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
+	sharedRail := railType == models.RailTypeNewReleases || railType == models.RailTypeContinueWatching
+	var shared []models.MovieCard
+	for rows.Next() {
+		var railId int
+		var card models.MovieCard
+		if err := scanMovieCard(rows, &card, &railId); err != nil {
+			return nil, err
+		}
+		if sharedRail {
+			shared = append(shared, card)
+			continue
+		}
+		cards[railId] = append(cards[railId], card)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, rail := range rails {
+		list := cards[rail.Id]
+		if sharedRail {
+			list = shared
+		}
+		if len(list) > rail.Size {
+			list = list[:rail.Size]
+		}
+		if list == nil {
+			list = []models.MovieCard{}
+		}
+		cards[rail.Id] = list
+	}
+
+	return cards, nil
+}
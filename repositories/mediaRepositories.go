@@ -2,11 +2,36 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"ozinshe_production/models"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// ErrMediaNotFound — у фильма нет кадра галереи с таким id
+var ErrMediaNotFound = errors.New("media not found")
+
+// ErrMediaOrderMismatch возвращается, если новый порядок перечисляет не все кадры фильма или содержит чужие id
+var ErrMediaOrderMismatch = errors.New("ids must list every screenshot of the movie exactly once")
+
+// Ключи скриншотов фильма m в порядке галереи — для запросов, которым не нужны подписи
+const movieScreenshotsSql = `ARRAY(
+    SELECT mm.storage_key FROM movie_media mm WHERE mm.movie_id = m.id ORDER BY mm.position, mm.id
+)`
+
+// Галерея фильма m одним JSON-массивом. Ключи совпадают с json-тегами MovieMediaItem
+const movieMediaItemsSql = `COALESCE((
+    SELECT json_agg(json_build_object(
+        'id', mm.id, 'key', mm.storage_key, 'position', mm.position,
+        'caption', mm.caption, 'alt_text', mm.alt_text, 'is_hero', mm.is_hero
+    ) ORDER BY mm.position, mm.id)
+    FROM movie_media mm
+    WHERE mm.movie_id = m.id
+), '[]')`
+
+const movieMediaItemColumns = `id, storage_key, position, caption, alt_text, is_hero`
+
 type MediaRepository struct {
     db *pgxpool.Pool
 }
@@ -15,79 +40,248 @@ func NewMediaRepository(db *pgxpool.Pool) *MediaRepository {
     return &MediaRepository{db: db}
 }
 
-// Получение медиафайлов фильма
+// Получение обложки и галереи фильма
 func (r *MediaRepository) GetMovieMedia(c context.Context, movieID int) (*models.MovieMedia, error) {
     var media models.MovieMedia
+    var items []models.MovieMediaItem
 
-    err := r.db.QueryRow(c, "SELECT cover, screenshots FROM movies WHERE id = $1", movieID).
-        Scan(&media.Cover, &media.Screenshots)
+    err := r.db.QueryRow(c, "SELECT m.cover, "+movieMediaItemsSql+" FROM movies m WHERE m.id = $1", movieID).
+        Scan(&media.Cover, &items)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return nil, ErrMovieNotFound
+    }
     if err != nil {
         return nil, err
     }
 
+    media.SetItems(items)
     return &media, nil
 }
 
-// Обновление обложки и скриншотов фильма
+// Обновление обложки и добавление скриншотов в конец галереи
 func (r *MediaRepository) UpdateMovieMedia(c context.Context, movieID int, cover *string, screenshots []string) error {
+    tx, err := r.db.Begin(c)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback(c)
+
+    if err := lockMovie(c, tx, movieID); err != nil {
+        return err
+    }
+
     if cover != nil {
-        _, err := r.db.Exec(c, "UPDATE movies SET cover = $1 WHERE id = $2", *cover, movieID)
+        _, err := tx.Exec(c, "UPDATE movies SET cover = $1 WHERE id = $2", *cover, movieID)
         if err != nil {
             return err
         }
     }
 
-    if len(screenshots) > 0 {
-        _, err := r.db.Exec(c, "UPDATE movies SET screenshots = array_cat(screenshots, $1) WHERE id = $2", screenshots, movieID)
-        if err != nil {
-            return err
+    if _, err := appendScreenshots(c, tx, movieID, screenshots); err != nil {
+        return err
+    }
+
+    return tx.Commit(c)
+}
+
+// Обновление отдельного медиафайла: замена обложки или новый скриншот в конце галереи.
+// Для скриншота возвращает id созданного кадра
+func (r *MediaRepository) UpdateSingleMovieMedia(c context.Context, movieID int, mediaType string, filename string) (int, error) {
+    tx, err := r.db.Begin(c)
+    if err != nil {
+        return 0, err
+    }
+    defer tx.Rollback(c)
+
+    if err := lockMovie(c, tx, movieID); err != nil {
+        return 0, err
+    }
+
+    var id int
+    switch mediaType {
+    case models.ImageKindCover:
+        _, err = tx.Exec(c, "UPDATE movies SET cover = $1 WHERE id = $2", filename, movieID)
+    case models.ImageKindScreenshot:
+        var ids []int
+        ids, err = appendScreenshots(c, tx, movieID, []string{filename})
+        if err == nil {
+            id = ids[0]
         }
     }
+    if err != nil {
+        return 0, err
+    }
 
-    return nil
+    return id, tx.Commit(c)
 }
 
-// Обновление отдельного медиафайла (обложка или один скриншот)
-func (r *MediaRepository) UpdateSingleMovieMedia(c context.Context, movieID int, mediaType string, filename string) error {
-    if mediaType == "cover" {
-        _, err := r.db.Exec(c, "UPDATE movies SET cover = $1 WHERE id = $2", filename, movieID)
-        return err
+// Удаление обложки или скриншота по ключу файла. Возвращает false, если у фильма нет такого файла
+func (r *MediaRepository) DeleteMovieMedia(c context.Context, movieID int, key string) (bool, error) {
+    tag, err := r.db.Exec(c, "UPDATE movies SET cover = NULL WHERE id = $1 AND cover = $2", movieID, key)
+    if err != nil {
+        return false, err
+    }
+    if tag.RowsAffected() > 0 {
+        return true, nil
     }
 
-    if mediaType == "screenshot" {
-        _, err := r.db.Exec(c, "UPDATE movies SET screenshots = array_append(screenshots, $1) WHERE id = $2", filename, movieID)
-        return err
+    tag, err = r.db.Exec(c, "DELETE FROM movie_media WHERE movie_id = $1 AND storage_key = $2", movieID, key)
+    if err != nil {
+        return false, err
     }
+    return tag.RowsAffected() > 0, nil
+}
 
-    return nil
+// DeleteMovieMediaItem удаляет кадр галереи и возвращает ключ его файла
+func (r *MediaRepository) DeleteMovieMediaItem(c context.Context, movieID, mediaID int) (string, error) {
+    var key string
+    err := r.db.QueryRow(c, "DELETE FROM movie_media WHERE id = $1 AND movie_id = $2 RETURNING storage_key", mediaID, movieID).
+        Scan(&key)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return "", ErrMediaNotFound
+    }
+    return key, err
 }
 
-// Удаление обложки или конкретного скриншота фильма
-func (r *MediaRepository) DeleteMovieMedia(c context.Context, movieID int, imageURL string) error {
-    var media models.MovieMedia
+// ReorderMovieMedia выставляет позиции кадров фильма в порядке mediaIds
+func (r *MediaRepository) ReorderMovieMedia(c context.Context, movieID int, mediaIds []int) error {
+    tx, err := r.db.Begin(c)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback(c)
 
-    // Получаем текущие данные
-    err := r.db.QueryRow(c, "SELECT cover, screenshots FROM movies WHERE id = $1", movieID).
-        Scan(&media.Cover, &media.Screenshots)
+    if err := lockMovie(c, tx, movieID); err != nil {
+        return err
+    }
+
+    // Список проверяется до UPDATE: повтор или пропуск кадра иначе нарушил бы уникальность позиций.
+    // Повторный id совпадает с одной строкой, поэтому matched окажется меньше длины списка
+    var total, matched int
+    err = tx.QueryRow(c, `
+        SELECT COUNT(*), COUNT(*) FILTER (WHERE id = ANY($2))
+        FROM movie_media
+        WHERE movie_id = $1
+    `, movieID, mediaIds).Scan(&total, &matched)
     if err != nil {
         return err
     }
+    if matched != len(mediaIds) || total != len(mediaIds) {
+        return ErrMediaOrderMismatch
+    }
 
-    // Проверяем, удаляем обложку или скриншот
-    if media.Cover != nil && *media.Cover == imageURL {
-        _, err := r.db.Exec(c, "UPDATE movies SET cover = NULL WHERE id = $1", movieID)
+    // Уникальность позиций проверяется в конце запроса, поэтому кадры можно менять местами
+    _, err = tx.Exec(c, `
+        UPDATE movie_media mm SET position = t.ord
+        FROM unnest($2::INT[]) WITH ORDINALITY AS t(id, ord)
+        WHERE mm.id = t.id AND mm.movie_id = $1
+    `, movieID, mediaIds)
+    if err != nil {
         return err
     }
 
-    // Удаляем скриншот из массива
-    updatedScreenshots := []string{}
-    for _, s := range media.Screenshots {
-        if s != imageURL {
-            updatedScreenshots = append(updatedScreenshots, s)
+    return tx.Commit(c)
+}
+
+// UpdateMovieMediaItem меняет подпись, альтернативный текст или отметку главного кадра
+func (r *MediaRepository) UpdateMovieMediaItem(c context.Context, movieID, mediaID int, patch models.MovieMediaPatch) (models.MovieMediaItem, error) {
+    var item models.MovieMediaItem
+
+    tx, err := r.db.Begin(c)
+    if err != nil {
+        return item, err
+    }
+    defer tx.Rollback(c)
+
+    // Блокировка фильма не даёт двум запросам одновременно выбрать разные главные кадры
+    if err := lockMovie(c, tx, movieID); err != nil {
+        return item, err
+    }
+
+    if patch.IsHero != nil && *patch.IsHero {
+        _, err := tx.Exec(c, "UPDATE movie_media SET is_hero = FALSE WHERE movie_id = $1 AND id <> $2 AND is_hero", movieID, mediaID)
+        if err != nil {
+            return item, err
         }
     }
 
-    // Обновляем список скриншотов
-    _, err = r.db.Exec(c, "UPDATE movies SET screenshots = $1 WHERE id = $2", updatedScreenshots, movieID)
+    err = tx.QueryRow(c, `
+        UPDATE movie_media
+        SET caption = COALESCE(@caption, caption),
+            alt_text = COALESCE(@altText, alt_text),
+            is_hero = COALESCE(@isHero, is_hero)
+        WHERE id = @mediaId AND movie_id = @movieId
+        RETURNING `+movieMediaItemColumns,
+        pgx.NamedArgs{
+            "caption": patch.Caption,
+            "altText": patch.AltText,
+            "isHero":  patch.IsHero,
+            "mediaId": mediaID,
+            "movieId": movieID,
+        }).Scan(&item.Id, &item.Key, &item.Position, &item.Caption, &item.AltText, &item.IsHero)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return item, ErrMediaNotFound
+    }
+    if err != nil {
+        return item, err
+    }
+
+    return item, tx.Commit(c)
+}
+
+// ReferencedMediaKeys возвращает все ключи (или старые ссылки) медиафайлов, на которые ссылается база:
+// обложки и кадры фильмов, постеры жанров и возрастных ограничений
+func (r *MediaRepository) ReferencedMediaKeys(c context.Context) ([]string, error) {
+    rows, err := r.db.Query(c, `
+        SELECT cover FROM movies WHERE cover <> ''
+        UNION SELECT storage_key FROM movie_media
+        UNION SELECT poster_url FROM genres WHERE poster_url <> ''
+        UNION SELECT poster_url FROM ages WHERE poster_url <> ''
+    `)
+    if err != nil {
+        return nil, err
+    }
+    return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// lockMovie блокирует строку фильма до конца транзакции, чтобы параллельные изменения галереи
+// не выдали одинаковые позиции. FOR NO KEY UPDATE не мешает вставкам, ссылающимся на фильм
+func lockMovie(c context.Context, tx pgx.Tx, movieID int) error {
+    var id int
+    err := tx.QueryRow(c, "SELECT id FROM movies WHERE id = $1 FOR NO KEY UPDATE", movieID).Scan(&id)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return ErrMovieNotFound
+    }
     return err
 }
+
+// appendScreenshots добавляет кадры в конец галереи и возвращает их id в том же порядке
+func appendScreenshots(c context.Context, tx pgx.Tx, movieID int, keys []string) ([]int, error) {
+    ids := make([]int, 0, len(keys))
+    if len(keys) == 0 {
+        return ids, nil
+    }
+
+    rows, err := tx.Query(c, `
+        WITH inserted AS (
+            INSERT INTO movie_media (movie_id, storage_key, position)
+            SELECT $1, t.storage_key, COALESCE((SELECT MAX(position) FROM movie_media WHERE movie_id = $1), 0) + t.ord
+            FROM unnest($2::TEXT[]) WITH ORDINALITY AS t(storage_key, ord)
+            RETURNING id, position
+        )
+        SELECT id FROM inserted ORDER BY position
+    `, movieID, keys)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var id int
+        if err := rows.Scan(&id); err != nil {
+            return nil, err
+        }
+        ids = append(ids, id)
+    }
+    return ids, rows.Err()
+}
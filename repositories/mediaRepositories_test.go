@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"ozinshe_production/models"
+	"slices"
+	"testing"
+)
+
+func insertGalleryMovie(t *testing.T, repo *MediaRepository) int {
+	t.Helper()
+	movieId, _ := insertTestMovie(t, repo.db, "Gallery")
+	return movieId
+}
+
+func galleryKeys(t *testing.T, repo *MediaRepository, movieId int) ([]string, []models.MovieMediaItem) {
+	t.Helper()
+	media, err := repo.GetMovieMedia(context.Background(), movieId)
+	if err != nil {
+		t.Fatalf("GetMovieMedia: %v", err)
+	}
+	return media.Screenshots, media.Items
+}
+
+func TestMovieGalleryOrderAndHero(t *testing.T) {
+	repo := NewMediaRepository(openTestDb(t))
+	ctx := context.Background()
+	movieId := insertGalleryMovie(t, repo)
+
+	if err := repo.UpdateMovieMedia(ctx, movieId, nil, []string{"a.jpg", "b.jpg"}); err != nil {
+		t.Fatalf("UpdateMovieMedia: %v", err)
+	}
+	cId, err := repo.UpdateSingleMovieMedia(ctx, movieId, models.ImageKindScreenshot, "c.jpg")
+	if err != nil {
+		t.Fatalf("UpdateSingleMovieMedia: %v", err)
+	}
+
+	keys, items := galleryKeys(t, repo, movieId)
+	if want := []string{"a.jpg", "b.jpg", "c.jpg"}; !slices.Equal(keys, want) {
+		t.Fatalf("screenshots = %v, want %v", keys, want)
+	}
+	if items[2].Id != cId {
+		t.Errorf("id of c.jpg = %d, want %d", items[2].Id, cId)
+	}
+
+	// Переставляем кадры местами: позиции пересекаются, пока запрос не закончится
+	order := []int{items[2].Id, items[0].Id, items[1].Id}
+	if err := repo.ReorderMovieMedia(ctx, movieId, order); err != nil {
+		t.Fatalf("ReorderMovieMedia: %v", err)
+	}
+	if keys, _ := galleryKeys(t, repo, movieId); !slices.Equal(keys, []string{"c.jpg", "a.jpg", "b.jpg"}) {
+		t.Errorf("screenshots after reorder = %v", keys)
+	}
+
+	for _, ids := range [][]int{order[:2], {order[0], order[0], order[1]}, {order[0], order[1], order[2], order[2] + 1000}} {
+		if err := repo.ReorderMovieMedia(ctx, movieId, ids); !errors.Is(err, ErrMediaOrderMismatch) {
+			t.Errorf("ReorderMovieMedia(%v) = %v, want ErrMediaOrderMismatch", ids, err)
+		}
+	}
+	if keys, _ := galleryKeys(t, repo, movieId); !slices.Equal(keys, []string{"c.jpg", "a.jpg", "b.jpg"}) {
+		t.Errorf("screenshots after rejected reorders = %v", keys)
+	}
+
+	hero, caption := true, "Финал"
+	if _, err := repo.UpdateMovieMediaItem(ctx, movieId, items[0].Id, models.MovieMediaPatch{IsHero: &hero}); err != nil {
+		t.Fatalf("UpdateMovieMediaItem: %v", err)
+	}
+	item, err := repo.UpdateMovieMediaItem(ctx, movieId, items[1].Id, models.MovieMediaPatch{IsHero: &hero, Caption: &caption})
+	if err != nil {
+		t.Fatalf("UpdateMovieMediaItem: %v", err)
+	}
+	if !item.IsHero || item.Caption != caption {
+		t.Errorf("updated item = %+v", item)
+	}
+
+	_, items = galleryKeys(t, repo, movieId)
+	var heroes []string
+	for _, item := range items {
+		if item.IsHero {
+			heroes = append(heroes, item.Key)
+		}
+	}
+	if !slices.Equal(heroes, []string{"b.jpg"}) {
+		t.Errorf("hero screenshots = %v, want [b.jpg]", heroes)
+	}
+
+	key, err := repo.DeleteMovieMediaItem(ctx, movieId, items[1].Id)
+	if err != nil || key != "b.jpg" {
+		t.Errorf("DeleteMovieMediaItem = %q, %v", key, err)
+	}
+	if _, err := repo.DeleteMovieMediaItem(ctx, movieId, items[1].Id); !errors.Is(err, ErrMediaNotFound) {
+		t.Errorf("second DeleteMovieMediaItem = %v, want ErrMediaNotFound", err)
+	}
+	if _, err := repo.UpdateMovieMediaItem(ctx, movieId, items[1].Id, models.MovieMediaPatch{Caption: &caption}); !errors.Is(err, ErrMediaNotFound) {
+		t.Errorf("UpdateMovieMediaItem of a deleted item = %v, want ErrMediaNotFound", err)
+	}
+
+	removed, err := repo.DeleteMovieMedia(ctx, movieId, "c.jpg")
+	if err != nil || !removed {
+		t.Errorf("DeleteMovieMedia = %v, %v", removed, err)
+	}
+	if keys, _ := galleryKeys(t, repo, movieId); !slices.Equal(keys, []string{"a.jpg"}) {
+		t.Errorf("screenshots after delete = %v", keys)
+	}
+}
+
+func TestMovieGalleryRequiresMovie(t *testing.T) {
+	repo := NewMediaRepository(openTestDb(t))
+	ctx := context.Background()
+
+	if _, err := repo.GetMovieMedia(ctx, -1); !errors.Is(err, ErrMovieNotFound) {
+		t.Errorf("GetMovieMedia = %v, want ErrMovieNotFound", err)
+	}
+	if err := repo.UpdateMovieMedia(ctx, -1, nil, []string{"a.jpg"}); !errors.Is(err, ErrMovieNotFound) {
+		t.Errorf("UpdateMovieMedia = %v, want ErrMovieNotFound", err)
+	}
+	if err := repo.ReorderMovieMedia(ctx, -1, []int{1}); !errors.Is(err, ErrMovieNotFound) {
+		t.Errorf("ReorderMovieMedia = %v, want ErrMovieNotFound", err)
+	}
+}
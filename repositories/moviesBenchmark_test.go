@@ -0,0 +1,220 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"ozinshe_production/models"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// legacyMoviesJoinSql — прежний запрос с LEFT JOIN всех связей. Оставлен только как точка сравнения:
+// на каждый фильм он возвращает жанры × категории × возрасты × эпизоды строк
+const legacyMoviesJoinSql = `
+	SELECT
+	m.id, m.title, m.description, m.release_year, m.director, m.producer,
+	m.runtime, m.keywords,
+	COALESCE(m.cover, '') AS cover,
+	` + movieScreenshotsSql + ` AS screenshots,
+	m.created_at, m.status, m.publish_at, m.unpublish_at,
+	mt.id, COALESCE(mt.title, '') AS movie_type_title,
+	g.id, COALESCE(g.title, '') AS genre_title,
+	c.id, COALESCE(c.title, '') AS category_title,
+	a.id, COALESCE(a.title, '') AS age_title,
+	COALESCE(s.id, 0) AS season_id, COALESCE(s.number, 0) AS season_number, COALESCE(s.movie_id, 0) AS season_movie_id,
+	COALESCE(e.id, 0) AS episode_id, COALESCE(e.number, 0) AS episode_number, COALESCE(e.video_url, '') AS episode_video_url, COALESCE(e.season_id, 0) AS episode_season_id
+	FROM movies m
+	LEFT JOIN movie_types mt ON mt.id = m.movie_type_id
+	LEFT JOIN movie_genres mg ON mg.movie_id = m.id
+	LEFT JOIN genres g ON mg.genre_id = g.id
+	LEFT JOIN movie_categories mc ON mc.movie_id = m.id
+	LEFT JOIN categories c ON mc.category_id = c.id
+	LEFT JOIN movie_ages ma ON ma.movie_id = m.id
+	LEFT JOIN ages a ON ma.age_id = a.id
+	LEFT JOIN seasons s ON s.movie_id = m.id
+	LEFT JOIN episodes e ON e.season_id = s.id
+	WHERE m.id = ANY($1)
+`
+
+// insertSeriesFixture создаёт movies сериалов с 5 жанрами, 3 категориями, 2 возрастами и 10 сезонами по 20 эпизодов
+func insertSeriesFixture(tb testing.TB, conn *pgxpool.Pool, movies int) []int {
+	tb.Helper()
+	ctx := context.Background()
+	suffix := uniqueSuffix()
+
+	var genreIds, categoryIds, ageIds []int
+	for i := 0; i < 5; i++ {
+		genreIds = append(genreIds, insertId(tb, conn, `INSERT INTO genres (title) VALUES ($1) RETURNING id`, fmt.Sprintf("Genre %d%s", i, suffix)))
+	}
+	for i := 0; i < 3; i++ {
+		categoryIds = append(categoryIds, insertId(tb, conn, `INSERT INTO categories (title) VALUES ($1) RETURNING id`, fmt.Sprintf("Category %d%s", i, suffix)))
+	}
+	for i := 0; i < 2; i++ {
+		ageIds = append(ageIds, insertId(tb, conn, `INSERT INTO ages (title) VALUES ($1) RETURNING id`, fmt.Sprintf("Age %d%s", i, suffix)))
+	}
+
+	var movieIds []int
+	for i := 0; i < movies; i++ {
+		movieId, _ := insertTestMovie(tb, conn, fmt.Sprintf("Bench %d", i))
+		movieIds = append(movieIds, movieId)
+
+		_, err := conn.Exec(ctx, `INSERT INTO movie_genres (movie_id, genre_id) SELECT $1, unnest($2::INT[])`, movieId, genreIds)
+		if err == nil {
+			_, err = conn.Exec(ctx, `INSERT INTO movie_categories (movie_id, category_id) SELECT $1, unnest($2::INT[])`, movieId, categoryIds)
+		}
+		if err == nil {
+			_, err = conn.Exec(ctx, `INSERT INTO movie_ages (movie_id, age_id) SELECT $1, unnest($2::INT[])`, movieId, ageIds)
+		}
+		if err == nil {
+			_, err = conn.Exec(ctx, `
+				WITH new_seasons AS (
+					INSERT INTO seasons (movie_id, number) SELECT $1, generate_series(1, 10) RETURNING id
+				)
+				INSERT INTO episodes (season_id, number, video_url)
+				SELECT s.id, n, 'https://cdn.example.com/episode.m3u8' FROM new_seasons s, generate_series(1, 20) n
+			`, movieId)
+		}
+		if err != nil {
+			tb.Fatalf("insert fixture: %v", err)
+		}
+	}
+
+	tb.Cleanup(func() {
+		conn.Exec(ctx, `DELETE FROM genres WHERE id = ANY($1)`, genreIds)
+		conn.Exec(ctx, `DELETE FROM categories WHERE id = ANY($1)`, categoryIds)
+		conn.Exec(ctx, `DELETE FROM ages WHERE id = ANY($1)`, ageIds)
+	})
+
+	return movieIds
+}
+
+// legacyFindAllByIds — прежний findAllByIds: join всех связей, сборка фильма из повторяющихся строк
+// и отсев повторов линейным поиском. Возвращает фильмы и число прочитанных строк
+func legacyFindAllByIds(b *testing.B, conn *pgxpool.Pool, ids []int) ([]models.Movie, int) {
+	rows, err := conn.Query(context.Background(), legacyMoviesJoinSql, ids)
+	if err != nil {
+		b.Fatalf("query: %v", err)
+	}
+	defer rows.Close()
+
+	moviesMap := make(map[int]*models.Movie)
+	count := 0
+	for rows.Next() {
+		var m models.Movie
+		var g models.Genre
+		var c models.Category
+		var a models.Ages
+		var s models.Season
+		var e models.Episode
+		var mt models.MovieType
+
+		err := rows.Scan(
+			&m.Id, &m.Title, &m.Description, &m.ReleaseYear, &m.Director,
+			&m.Producer, &m.Runtime, &m.KeyWords, &m.Media.Cover, &m.Media.Screenshots,
+			&m.CreatedAt, &m.Status, &m.PublishAt, &m.UnpublishAt,
+			&mt.Id, &mt.Title,
+			&g.Id, &g.Title,
+			&c.Id, &c.Title,
+			&a.Id, &a.Title,
+			&s.Id, &s.Number, &s.MovieID,
+			&e.Id, &e.Number, &e.VideoURL, &e.SeasonID,
+		)
+		if err != nil {
+			b.Fatalf("scan row: %v", err)
+		}
+		count++
+
+		movie, exists := moviesMap[m.Id]
+		if !exists {
+			m.MovieType = mt.Title
+			m.MovieTypeId = mt.Id
+			moviesMap[m.Id] = &m
+			movie = &m
+		}
+		legacyAddMovieRelations(movie, g, c, a, s, e)
+	}
+	if err := rows.Err(); err != nil {
+		b.Fatalf("read rows: %v", err)
+	}
+
+	movies := make([]models.Movie, 0, len(ids))
+	for _, id := range ids {
+		if movie, ok := moviesMap[id]; ok {
+			movies = append(movies, *movie)
+		}
+	}
+	return movies, count
+}
+
+// legacyAddMovieRelations — прежний addMovieRelations: связь из строки join-а добавляется,
+// если её id ещё не встречался в уже собранном списке
+func legacyAddMovieRelations(movie *models.Movie, g models.Genre, c models.Category, a models.Ages, s models.Season, e models.Episode) {
+	if g.Id != 0 && !slices.ContainsFunc(movie.Genres, func(genre models.Genre) bool { return genre.Id == g.Id }) {
+		movie.Genres = append(movie.Genres, g)
+	}
+	if c.Id != 0 && !slices.ContainsFunc(movie.Categories, func(category models.Category) bool { return category.Id == c.Id }) {
+		movie.Categories = append(movie.Categories, c)
+	}
+	if a.Id != 0 && !slices.ContainsFunc(movie.Ages, func(age models.Ages) bool { return age.Id == a.Id }) {
+		movie.Ages = append(movie.Ages, a)
+	}
+	if s.Id == 0 {
+		return
+	}
+	if !slices.ContainsFunc(movie.Seasons, func(season models.Season) bool { return season.Id == s.Id }) {
+		movie.Seasons = append(movie.Seasons, s)
+	}
+	if e.Id == 0 {
+		return
+	}
+	for i, season := range movie.Seasons {
+		if season.Id == s.Id && !slices.ContainsFunc(season.Episodes, func(episode models.Episode) bool { return episode.Id == e.Id }) {
+			movie.Seasons[i].Episodes = append(movie.Seasons[i].Episodes, e)
+		}
+	}
+}
+
+// BenchmarkMoviesLoading сравнивает прежнюю загрузку через join с отсевом повторов и findAllByIds
+// с json_agg-подзапросами на одном сериале (FindById) и на странице из 20 сериалов (FindAll).
+// Оба варианта сканируют строки в модели целиком. rows/op — число строк, которое возвращает база.
+// Запуск: TEST_DB_CONNECTION_STRING=... go test ./repositories -run '^$' -bench MoviesLoading -benchmem
+func BenchmarkMoviesLoading(b *testing.B) {
+	conn := openTestDb(b)
+	movieIds := insertSeriesFixture(b, conn, 20)
+	repo := NewMoviesRepository(conn)
+
+	for _, size := range []struct {
+		name string
+		ids  []int
+	}{
+		{name: "one_series", ids: movieIds[:1]},
+		{name: "page_of_20", ids: movieIds},
+	} {
+		b.Run(size.name+"/cartesian_join", func(b *testing.B) {
+			rows := 0
+			for i := 0; i < b.N; i++ {
+				var movies []models.Movie
+				movies, rows = legacyFindAllByIds(b, conn, size.ids)
+				if len(movies) != len(size.ids) {
+					b.Fatalf("legacyFindAllByIds returned %d movies, want %d", len(movies), len(size.ids))
+				}
+			}
+			b.ReportMetric(float64(rows), "rows/op")
+		})
+
+		b.Run(size.name+"/json_agg", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				movies, err := repo.findAllByIds(context.Background(), size.ids)
+				if err != nil {
+					b.Fatalf("findAllByIds: %v", err)
+				}
+				if len(movies) != len(size.ids) {
+					b.Fatalf("findAllByIds returned %d movies, want %d", len(movies), len(size.ids))
+				}
+			}
+			b.ReportMetric(float64(len(size.ids)), "rows/op")
+		})
+	}
+}
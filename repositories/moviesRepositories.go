@@ -2,13 +2,26 @@ package repositories
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"ozinshe_production/models"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// ErrInvalidCursor возвращается, если курсор повреждён или выдан для другой сортировки
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrMovieNotFound возвращается, если фильма нет или он не опубликован
+var ErrMovieNotFound = errors.New("movie not found")
+
 type MoviesRepository struct {
 	db *pgxpool.Pool
 }
@@ -17,232 +30,271 @@ func NewMoviesRepository(conn *pgxpool.Pool) *MoviesRepository {
 	return &MoviesRepository{db: conn}
 }
 
+// FindById возвращает фильм со всеми связями. Если фильма нет, Id результата равен нулю
 func (r *MoviesRepository) FindById(c context.Context, id int) (models.Movie, error) {
-	sql := `
-	SELECT 
-	m.id, m.title, m.release_year, m.runtime, m.keywords, m.description, m.director, 
-	m.producer,
-	COALESCE(m.cover, '') AS cover, 
-	COALESCE(m.screenshots, '{}'::TEXT[]) AS screenshots,
-	COALESCE(mt.title, '') AS movie_type_title,
-	g.id, COALESCE(g.title, '') AS genre_title,
-	c.id, COALESCE(c.title, '') AS category_title,
-	a.id, COALESCE(a.title, '') AS age_title,
-	COALESCE(s.id, 0) AS season_id, COALESCE(s.number, 0) AS season_number, COALESCE(s.movie_id, 0) AS season_movie_id,
-	COALESCE(e.id, 0) AS episode_id, COALESCE(e.number, 0) AS episode_number, COALESCE(e.video_url, '') AS episode_video_url, COALESCE(e.season_id, 0) AS episode_season_id
-	FROM movies m
-	LEFT JOIN movie_types mt ON mt.id = m.movie_type_id 
-	LEFT JOIN movie_genres mg ON mg.movie_id = m.id
-	LEFT JOIN genres g ON mg.genre_id = g.id
-	LEFT JOIN movie_categories mc ON mc.movie_id = m.id
-	LEFT JOIN categories c ON mc.category_id = c.id
-	LEFT JOIN movie_ages ma ON ma.movie_id = m.id
-	LEFT JOIN ages a ON ma.age_id = a.id
-	LEFT JOIN seasons s ON s.movie_id = m.id
-	LEFT JOIN episodes e ON e.season_id = s.id
-	WHERE m.id = $1
-	`
-
-	rows, err := r.db.Query(c, sql, id)
-	if err != nil {
+	movies, err := r.findAllByIds(c, []int{id})
+	if err != nil || len(movies) == 0 {
 		return models.Movie{}, err
 	}
-	defer rows.Close()
+	return movies[0], nil
+}
 
-	var movie models.Movie
-	genresMap := make(map[int]models.Genre)
-	categoriesMap := make(map[int]models.Category)
-	agesMap := make(map[int]models.Ages)
-	seasonsMap := make(map[int]models.Season)
-	seasonEpisodesMap := make(map[int][]models.Episode)
 
-	for rows.Next() {
-		var g 	models.Genre
-		var c 	models.Category
-		var a 	models.Ages
-		var s 	models.Season
-		var e 	models.Episode
-		var mt 	models.MovieType
+// FindAll возвращает страницу фильмов, отсортированную по filters.Sort с добором по id,
+// и общее количество фильмов, подходящих под фильтры
+func (r *MoviesRepository) FindAll(c context.Context, filters models.Moviesfilters) (models.MoviesPage, error) {
+	if err := filters.Validate(); err != nil {
+		return models.MoviesPage{}, err
+	}
 
-		err := rows.Scan(
-			&movie.Id, &movie.Title, &movie.ReleaseYear, &movie.Runtime, &movie.KeyWords,
-			&movie.Description, &movie.Director, &movie.Producer, &movie.Media.Cover, &movie.Media.Screenshots,
-			&mt.Title,
-			&g.Id, &g.Title,
-			&c.Id, &c.Title,
-			&a.Id, &a.Title,
-			&s.Id, &s.Number, &s.MovieID,
-			&e.Id, &e.Number, &e.VideoURL, &e.SeasonID,
-		)
-		if err != nil {
-			return models.Movie{}, err
-		}
+	params := pgx.NamedArgs{}
+	where := moviesFilterSql(filters, params)
 
-		movie.MovieType = mt.Title
-		movie.MovieTypeId = mt.Id
+	var total int
+	err := r.db.QueryRow(c, "SELECT COUNT(*) FROM movies m WHERE "+where, params).Scan(&total)
+	if err != nil {
+		return models.MoviesPage{}, err
+	}
 
-		if g.Id != 0 {
-			genresMap[g.Id] = g
-		}
-		if c.Id != 0 {
-			categoriesMap[c.Id] = c
+	pageWhere := where
+	if filters.Cursor != "" {
+		cursor, err := decodeMoviesCursor(filters)
+		if err != nil {
+			return models.MoviesPage{}, err
 		}
-		if a.Id != 0 {
-			agesMap[a.Id] = a
+		comparison := ">"
+		if filters.Order == "desc" {
+			comparison = "<"
 		}
-		if s.Id != 0 {
-			if _, exists := seasonsMap[s.Id]; !exists {
-				seasonsMap[s.Id] = s
-			}
+		pageWhere = fmt.Sprintf("%s AND (m.%s, m.id) %s (@cursorValue, @cursorId)", where, filters.Sort, comparison)
+		params["cursorValue"] = cursor.value
+		params["cursorId"] = cursor.Id
+	}
+
+	// Берём на одну запись больше, чтобы понять, есть ли следующая страница
+	params["limit"] = filters.Limit + 1
+	sql := fmt.Sprintf(`
+		SELECT m.id, m.title, m.release_year, m.created_at
+		FROM movies m
+		WHERE %s
+		ORDER BY m.%s %s, m.id %s
+		LIMIT @limit
+	`, pageWhere, filters.Sort, filters.Order, filters.Order)
+
+	rows, err := r.db.Query(c, sql, params)
+	if err != nil {
+		return models.MoviesPage{}, err
+	}
+	defer rows.Close()
+
+	var keys []models.Movie
+	for rows.Next() {
+		var m models.Movie
+		if err := rows.Scan(&m.Id, &m.Title, &m.ReleaseYear, &m.CreatedAt); err != nil {
+			return models.MoviesPage{}, err
 		}
-		if e.Id != 0 {
-			exists := false
-			for _, ep := range seasonEpisodesMap[s.Id] {
-				if ep.Id == e.Id {
-					exists = true
-					break
-				}
-			}
-			if !exists {
-				seasonEpisodesMap[s.Id] = append(seasonEpisodesMap[s.Id], e)
-			}
+		keys = append(keys, m)
+	}
+	if err := rows.Err(); err != nil {
+		return models.MoviesPage{}, err
+	}
+
+	page := models.MoviesPage{Total: total}
+	if len(keys) > filters.Limit {
+		keys = keys[:filters.Limit]
+		page.NextCursor, err = encodeMoviesCursor(filters, keys[len(keys)-1])
+		if err != nil {
+			return models.MoviesPage{}, err
 		}
 	}
 
-	// Добавляем уникальные жанры, категории и возрастные ограничения
-	for _, genre := range genresMap {
-		movie.Genres = append(movie.Genres, genre)
+	ids := make([]int, 0, len(keys))
+	for _, key := range keys {
+		ids = append(ids, key.Id)
 	}
-	for _, category := range categoriesMap {
-		movie.Categories = append(movie.Categories, category)
+
+	page.Movies, err = r.findAllByIds(c, ids)
+	if err != nil {
+		return models.MoviesPage{}, err
 	}
-	for _, age := range agesMap {
-		movie.Ages = append(movie.Ages, age)
+
+	return page, nil
+}
+
+// moviesFilterSql собирает условие WHERE по фильтрам. Связи проверяются через EXISTS и подзапросы,
+// чтобы фильтр не размножал строки фильмов
+func moviesFilterSql(filters models.Moviesfilters, params pgx.NamedArgs) string {
+	sql := "1=1"
+
+	if len(filters.GenreIds) > 0 {
+		sql = fmt.Sprintf("%s and %s", sql, linkFilterSql("movie_genres", "genre_id", "genreIds", filters.GenreMatch))
+		params["genreIds"] = filters.GenreIds
+		params["genreIdsCount"] = len(filters.GenreIds)
 	}
-	// Добавляем сезоны и их эпизоды
-	for _, season := range seasonsMap {
-		season.Episodes = seasonEpisodesMap[season.Id]
-		movie.Seasons = append(movie.Seasons, season)
+
+	if len(filters.CategoryIds) > 0 {
+		sql = fmt.Sprintf("%s and %s", sql, linkFilterSql("movie_categories", "category_id", "categoryIds", filters.CategoryMatch))
+		params["categoryIds"] = filters.CategoryIds
+		params["categoryIdsCount"] = len(filters.CategoryIds)
 	}
 
-	if err = rows.Err(); err != nil {
-		return models.Movie{}, err
+	// У фильма один тип, поэтому список типов всегда работает как any
+	if len(filters.TypeIds) > 0 {
+		sql = fmt.Sprintf("%s and m.movie_type_id = ANY(@typeIds)", sql)
+		params["typeIds"] = filters.TypeIds
 	}
 
-	return movie, nil
-}
+	if len(filters.AgeIds) > 0 {
+		sql = fmt.Sprintf("%s and %s", sql, linkFilterSql("movie_ages", "age_id", "ageIds", filters.AgeMatch))
+		params["ageIds"] = filters.AgeIds
+		params["ageIdsCount"] = len(filters.AgeIds)
+	}
 
+	if len(filters.Statuses) > 0 {
+		sql = fmt.Sprintf("%s and m.status = ANY(@statuses)", sql)
+		params["statuses"] = filters.Statuses
+	}
 
-func (r *MoviesRepository) FindAll(c context.Context, filters models.Moviesfilters) ([]models.Movie, error) {
-	sql := `
-	SELECT 
-	m.id, m.title, m.description, m.release_year, m.director, m.producer, 
-	m.runtime, m.keywords, 
-	COALESCE(m.cover, '') AS cover, 
-	COALESCE(m.screenshots, '{}'::TEXT[]) AS screenshots, 
-	mt.id, COALESCE(mt.title, '') AS movie_type_title,
-	g.id, COALESCE(g.title, '') AS genre_title, 
-	c.id, COALESCE(c.title, '') AS category_title,
-	a.id, COALESCE(a.title, '') AS age_title,
-	COALESCE(s.id, 0) AS season_id, COALESCE(s.number, 0) AS season_number, COALESCE(s.movie_id, 0) AS season_movie_id,
-	COALESCE(e.id, 0) AS episode_id, COALESCE(e.number, 0) AS episode_number, COALESCE(e.video_url, '') AS episode_video_url, COALESCE(e.season_id, 0) AS episode_season_id
-	FROM movies m
-	LEFT JOIN movie_types mt ON mt.id = m.movie_type_id 
-	LEFT JOIN movie_genres mg ON mg.movie_id = m.id
-	LEFT JOIN genres g ON mg.genre_id = g.id
-	LEFT JOIN movie_categories mc ON mc.movie_id = m.id
-	LEFT JOIN categories c ON mc.category_id = c.id
-	LEFT JOIN movie_ages ma ON ma.movie_id = m.id
-	LEFT JOIN ages a ON ma.age_id = a.id
-	LEFT JOIN seasons s ON s.movie_id = m.id
-	LEFT JOIN episodes e ON e.season_id = s.id
-	where 1=1
-	`
+	if filters.YearFrom != 0 {
+		sql = fmt.Sprintf("%s and m.release_year >= @yearFrom", sql)
+		params["yearFrom"] = filters.YearFrom
+	}
+	if filters.YearTo != 0 {
+		sql = fmt.Sprintf("%s and m.release_year <= @yearTo", sql)
+		params["yearTo"] = filters.YearTo
+	}
 
-	params := pgx.NamedArgs{}
+	if filters.RuntimeFrom != 0 {
+		sql = fmt.Sprintf("%s and m.runtime >= @runtimeFrom", sql)
+		params["runtimeFrom"] = filters.RuntimeFrom
+	}
+	if filters.RuntimeTo != 0 {
+		sql = fmt.Sprintf("%s and m.runtime <= @runtimeTo", sql)
+		params["runtimeTo"] = filters.RuntimeTo
+	}
 
-	if filters.GenreIds != "" {
-		sql = fmt.Sprintf("%s and g.id = @genreId", sql)
-		params["genreId"] = filters.GenreIds
+	if filters.Director != "" {
+		sql = fmt.Sprintf("%s and m.director ILIKE '%%' || @director || '%%' ESCAPE '\\'", sql)
+		params["director"] = escapeLike(filters.Director)
 	}
+	if filters.Producer != "" {
+		sql = fmt.Sprintf("%s and m.producer ILIKE '%%' || @producer || '%%' ESCAPE '\\'", sql)
+		params["producer"] = escapeLike(filters.Producer)
+	}
+
+	if len(filters.Keywords) > 0 {
+		keywords := make([]string, 0, len(filters.Keywords))
+		for _, keyword := range filters.Keywords {
+			keyword = strings.ToLower(keyword)
+			if !slices.Contains(keywords, keyword) {
+				keywords = append(keywords, keyword)
+			}
+		}
 
-	if filters.CategoryIds != "" {
-		sql = fmt.Sprintf("%s and c.id = @categoryId", sql)
-		params["categoryId"] = filters.CategoryIds
+		if filters.KeywordMatch == models.MatchAll {
+			sql = fmt.Sprintf("%s and (SELECT COUNT(DISTINCT lower(k)) FROM unnest(m.keywords) k WHERE lower(k) = ANY(@keywords)) = @keywordsCount", sql)
+		} else {
+			sql = fmt.Sprintf("%s and EXISTS (SELECT 1 FROM unnest(m.keywords) k WHERE lower(k) = ANY(@keywords))", sql)
+		}
+		params["keywords"] = keywords
+		params["keywordsCount"] = len(keywords)
 	}
 
-	if filters.TypeIds != "" {
-		sql = fmt.Sprintf("%s and mt.id = @typeid", sql)
-		params["typeid"] = filters.TypeIds
+	return sql
+}
+
+// linkFilterSql фильтрует по таблице связей: any — хотя бы одна связь из списка, all — все связи из списка.
+// Список идентификаторов должен быть без повторов
+func linkFilterSql(table, column, param, match string) string {
+	if match == models.MatchAll {
+		return fmt.Sprintf("(SELECT COUNT(*) FROM %s l WHERE l.movie_id = m.id AND l.%s = ANY(@%s)) = @%sCount", table, column, param, param)
 	}
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM %s l WHERE l.movie_id = m.id AND l.%s = ANY(@%s))", table, column, param)
+}
 
-	if filters.AgeIds != "" {
-		sql = fmt.Sprintf("%s and a.id = @ageId", sql)
-		params["ageId"] = filters.AgeIds
+// Фильм одной строкой: связи собираются json_agg-подзапросами, а не join-ом всех таблиц,
+// поэтому число строк равно числу фильмов, а не произведению жанров, категорий и эпизодов.
+// Жанры, категории и возрасты идут по названию без учёта регистра, сезоны и эпизоды — по номеру,
+// при равенстве решает id. Ключи JSON совпадают с полями моделей
+const selectMoviesSql = `
+	SELECT
+	m.id, m.title, m.description, m.release_year, m.director, m.producer,
+	m.runtime, m.keywords,
+	COALESCE(m.cover, '') AS cover,
+	`+movieMediaItemsSql+` AS gallery,
+	m.created_at, m.status, m.publish_at, m.unpublish_at,
+	m.movie_type_id, COALESCE(mt.title, '') AS movie_type_title,
+	COALESCE((
+		SELECT json_agg(json_build_object('Id', g.id, 'Title', g.title) ORDER BY lower(g.title), g.id)
+		FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id
+		WHERE mg.movie_id = m.id
+	), '[]') AS genres,
+	COALESCE((
+		SELECT json_agg(json_build_object('Id', c.id, 'Title', c.title) ORDER BY lower(c.title), c.id)
+		FROM movie_categories mc JOIN categories c ON c.id = mc.category_id
+		WHERE mc.movie_id = m.id
+	), '[]') AS categories,
+	COALESCE((
+		SELECT json_agg(json_build_object('Id', a.id, 'Title', a.title) ORDER BY lower(a.title), a.id)
+		FROM movie_ages ma JOIN ages a ON a.id = ma.age_id
+		WHERE ma.movie_id = m.id
+	), '[]') AS ages,
+	COALESCE((
+		SELECT json_agg(json_build_object(
+			'Id', s.id, 'Number', s.number, 'MovieID', s.movie_id,
+			'Episodes', COALESCE((
+				SELECT json_agg(json_build_object('Id', e.id, 'Number', e.number, 'SeasonID', e.season_id, 'VideoURL', e.video_url) ORDER BY e.number, e.id)
+				FROM episodes e
+				WHERE e.season_id = s.id
+			), '[]')
+		) ORDER BY s.number, s.id)
+		FROM seasons s
+		WHERE s.movie_id = m.id
+	), '[]') AS seasons
+	FROM movies m
+	LEFT JOIN movie_types mt ON mt.id = m.movie_type_id
+`
+
+// findAllByIds загружает фильмы со всеми связями и возвращает их в порядке ids
+func (r *MoviesRepository) findAllByIds(c context.Context, ids []int) ([]models.Movie, error) {
+	movies := make([]models.Movie, 0, len(ids))
+	if len(ids) == 0 {
+		return movies, nil
 	}
-	
-	rows, err := r.db.Query(c, sql, params)
+
+	rows, err := r.db.Query(c, selectMoviesSql+" WHERE m.id = ANY($1)", ids)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	moviesMap := make(map[int]*models.Movie)
-
+	moviesMap := make(map[int]models.Movie, len(ids))
 	for rows.Next() {
 		var m models.Movie
-		var g models.Genre
-		var c models.Category
-		var a models.Ages
-		var s models.Season
-		var e models.Episode
-		var mt models.MovieType
-
+		var gallery []models.MovieMediaItem
 		err := rows.Scan(
 			&m.Id, &m.Title, &m.Description, &m.ReleaseYear, &m.Director,
-			&m.Producer, &m.Runtime, &m.KeyWords, &m.Media.Cover, &m.Media.Screenshots,
-			&mt.Id, &mt.Title,
-			&g.Id, &g.Title,
-			&c.Id, &c.Title,
-			&a.Id, &a.Title,
-			&s.Id, &s.Number, &s.MovieID,
-			&e.Id, &e.Number, &e.VideoURL, &e.SeasonID,
+			&m.Producer, &m.Runtime, &m.KeyWords, &m.Media.Cover, &gallery,
+			&m.CreatedAt, &m.Status, &m.PublishAt, &m.UnpublishAt,
+			&m.MovieTypeId, &m.MovieType,
+			&m.Genres, &m.Categories, &m.Ages, &m.Seasons,
 		)
-		
 		if err != nil {
 			return nil, err
 		}
 
-		existingMovie, exists := moviesMap[m.Id]
-		if !exists {
-			m.MovieType = mt.Title
-			m.MovieTypeId = mt.Id
-			moviesMap[m.Id] = &m
-			existingMovie = &m
-		}
-
-		if g.Id != 0 && !containsGenre(existingMovie.Genres, g) {
-			existingMovie.Genres = append(existingMovie.Genres, g)
-		}
-		if c.Id != 0 && !containsCategory(existingMovie.Categories, c) {
-			existingMovie.Categories = append(existingMovie.Categories, c)
-		}
-		if a.Id != 0 && !containsAge(existingMovie.Ages, a) {
-			existingMovie.Ages = append(existingMovie.Ages, a)
-		}
-		if s.Id != 0 && !containsSeason(existingMovie.Seasons, s) {
-			existingMovie.Seasons = append(existingMovie.Seasons, s)
-		}
-		for i, season := range existingMovie.Seasons {
-			if season.Id == s.Id && !containsEpisode(existingMovie.Seasons[i].Episodes, e) {
-				existingMovie.Seasons[i].Episodes = append(existingMovie.Seasons[i].Episodes, e)
-			}
-		}
+		m.Media.SetItems(gallery)
+		moviesMap[m.Id] = m
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	movies := make([]models.Movie, 0, len(moviesMap))
-	for _, movie := range moviesMap {
-		movies = append(movies, *movie)
+	// Порядок страницы задаёт запрос с сортировкой
+	for _, id := range ids {
+		if movie, ok := moviesMap[id]; ok {
+			movies = append(movies, movie)
+		}
 	}
 
 	return movies, nil
@@ -411,78 +463,218 @@ func (r *MoviesRepository) Delete(c context.Context, movieID int) error {
 	return nil
 }
 
-func (r *MoviesRepository) SearchMovies(c context.Context, query string) ([]models.Movie, error) {
-	rows, err := r.db.Query(c, `
-        SELECT id, title, release_year, runtime, 
-               keywords, description, director, 
-               producer, cover, screenshots, movie_type_id
-        FROM movies
-        WHERE title ILIKE '%' || $1 || '%'
-        ORDER BY release_year DESC
-    `, query)
+// UpdateStatus переводит фильм в состояние change.Status с уже проверенным расписанием
+func (r *MoviesRepository) UpdateStatus(c context.Context, id int, change models.MovieStatusChange) error {
+	tag, err := r.db.Exec(c, `
+		UPDATE movies SET status = $1, publish_at = $2, unpublish_at = $3
+		WHERE id = $4
+	`, change.Status, change.PublishAt, change.UnpublishAt, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
 
+// IsPublished проверяет, что фильм существует и опубликован
+func (r *MoviesRepository) IsPublished(c context.Context, id int) (bool, error) {
+	var published bool
+	err := r.db.QueryRow(c, `
+		SELECT EXISTS(SELECT 1 FROM movies WHERE id = $1 AND status = 'published')
+	`, id).Scan(&published)
+	return published, err
+}
+
+// FindRelated возвращает до limit опубликованных фильмов, похожих на фильм id: больше общих жанров
+// и категорий — выше, при равенстве новее
+func (r *MoviesRepository) FindRelated(c context.Context, id, limit int) ([]models.MovieCard, error) {
+	rows, err := r.db.Query(c, `
+		SELECT `+movieCardColumns+`
+		FROM (
+			SELECT movie_id, COUNT(*) AS shared
+			FROM (
+				SELECT other.movie_id
+				FROM movie_genres own
+				JOIN movie_genres other ON other.genre_id = own.genre_id AND other.movie_id <> own.movie_id
+				WHERE own.movie_id = $1
+				UNION ALL
+				SELECT other.movie_id
+				FROM movie_categories own
+				JOIN movie_categories other ON other.category_id = own.category_id AND other.movie_id <> own.movie_id
+				WHERE own.movie_id = $1
+			) links
+			GROUP BY movie_id
+		) related
+		JOIN movies m ON m.id = related.movie_id
+		LEFT JOIN movie_types mt ON mt.id = m.movie_type_id
+		WHERE m.status = 'published'
+		ORDER BY related.shared DESC, m.created_at DESC, m.id DESC
+		LIMIT $2
+	`, id, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var movies []models.Movie
+	cards := make([]models.MovieCard, 0)
 	for rows.Next() {
-		var movie models.Movie
-		if err := rows.Scan(
-			&movie.Id, &movie.Title, &movie.ReleaseYear, &movie.Runtime,
-			&movie.KeyWords, &movie.Description, &movie.Director,
-			&movie.Producer, &movie.Media.Cover, &movie.Media.Screenshots, &movie.MovieTypeId,
-		); err != nil {
+		var card models.MovieCard
+		if err := scanMovieCard(rows, &card); err != nil {
 			return nil, err
 		}
-		movies = append(movies, movie)
+		cards = append(cards, card)
 	}
-	return movies, nil
+	return cards, rows.Err()
 }
 
-func containsGenre(genres []models.Genre, g models.Genre) bool {
-	for _, genre := range genres {
-		if genre.Id == g.Id {
-			return true
-		}
+// ApplySchedule публикует фильмы, у которых наступил publish_at, и снимает с публикации
+// фильмы, у которых наступил unpublish_at
+func (r *MoviesRepository) ApplySchedule(c context.Context) (int64, int64, error) {
+	published, err := r.db.Exec(c, `
+		UPDATE movies SET status = 'published'
+		WHERE status = 'scheduled' AND publish_at <= NOW()
+	`)
+	if err != nil {
+		return 0, 0, err
 	}
-	return false
-}
 
-func containsCategory(categories []models.Category, c models.Category) bool {
-	for _, category := range categories {
-		if category.Id == c.Id {
-			return true
-		}
+	archived, err := r.db.Exec(c, `
+		UPDATE movies SET status = 'archived'
+		WHERE status = 'published' AND unpublish_at <= NOW()
+	`)
+	if err != nil {
+		return published.RowsAffected(), 0, err
 	}
-	return false
+
+	return published.RowsAffected(), archived.RowsAffected(), nil
 }
 
-func containsAge(ages []models.Ages, a models.Ages) bool {
-	for _, age := range ages {
-		if age.Id == a.Id {
-			return true
+// SearchMovies ищет по взвешенному tsvector (название, ключевые слова, режиссёр/продюсер, описание)
+// с префиксным совпадением слов. Опечатки в названии покрываются триграммной похожестью
+func (r *MoviesRepository) SearchMovies(c context.Context, query string, limit int) ([]models.MovieSearchResult, error) {
+	results := make([]models.MovieSearchResult, 0)
+
+	tsQuery := prefixTsQuery(query)
+	if tsQuery == "" {
+		return results, nil
+	}
+
+	// Подсветка строится по simple-запросу: он совпадает с исходными словами на любом языке
+	rows, err := r.db.Query(c, `
+		WITH q AS (
+			SELECT to_tsquery('simple', @tsQuery) || to_tsquery('russian', @tsQuery) || to_tsquery('english', @tsQuery) AS query,
+			       to_tsquery('simple', @tsQuery) AS simple_query
+		)
+		SELECT m.id, m.title, m.release_year, m.runtime,
+		       m.keywords, m.description, m.director,
+		       m.producer, m.cover, `+movieScreenshotsSql+`, m.movie_type_id,
+		       ts_rank(m.search_vector, q.query) + word_similarity(@query, m.title) AS rank,
+		       ts_headline('simple', m.title, q.simple_query, 'StartSel=<b>, StopSel=</b>, HighlightAll=true'),
+		       ts_headline('simple', m.description, q.simple_query, 'StartSel=<b>, StopSel=</b>, MinWords=15, MaxWords=35, MaxFragments=2')
+		FROM movies m, q
+		WHERE (m.search_vector @@ q.query OR @query <% m.title) AND m.status = 'published'
+		ORDER BY rank DESC, m.id
+		LIMIT @limit
+	`, pgx.NamedArgs{"tsQuery": tsQuery, "query": query, "limit": limit})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result models.MovieSearchResult
+		if err := rows.Scan(
+			&result.Id, &result.Title, &result.ReleaseYear, &result.Runtime,
+			&result.KeyWords, &result.Description, &result.Director,
+			&result.Producer, &result.Media.Cover, &result.Media.Screenshots, &result.MovieTypeId,
+			&result.Rank, &result.Highlights.Title, &result.Highlights.Description,
+		); err != nil {
+			return nil, err
 		}
+		results = append(results, result)
 	}
-	return false
+	return results, rows.Err()
 }
 
-func containsSeason(seasons []models.Season, s models.Season) bool {
-	for _, season := range seasons {
-		if season.Id == s.Id {
-			return true
-		}
+// prefixTsQuery превращает пользовательский ввод в "слово1:* & слово2:*". Всё, кроме букв и цифр,
+// отбрасывается, поэтому синтаксис tsquery из ввода попасть в запрос не может
+func prefixTsQuery(query string) string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		words[i] = word + ":*"
 	}
-	return false
+	return strings.Join(words, " & ")
 }
 
-func containsEpisode(episodes []models.Episode, e models.Episode) bool {
-	for _, episode := range episodes {
-		if episode.Id == e.Id {
-			return true
-		}
+// moviesCursor хранит ключ сортировки последнего фильма страницы
+type moviesCursor struct {
+	Sort  string          `json:"sort"`
+	Order string          `json:"order"`
+	Value json.RawMessage `json:"value"`
+	Id    int             `json:"id"`
+
+	value any
+}
+
+func encodeMoviesCursor(filters models.Moviesfilters, last models.Movie) (string, error) {
+	var value any
+	switch filters.Sort {
+	case models.MoviesSortTitle:
+		value = last.Title
+	case models.MoviesSortReleaseYear:
+		value = last.ReleaseYear
+	default:
+		value = last.CreatedAt
 	}
-	return false
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(moviesCursor{Sort: filters.Sort, Order: filters.Order, Value: raw, Id: last.Id})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
 }
 
+func decodeMoviesCursor(filters models.Moviesfilters) (moviesCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(filters.Cursor)
+	if err != nil {
+		return moviesCursor{}, ErrInvalidCursor
+	}
+
+	var cursor moviesCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return moviesCursor{}, ErrInvalidCursor
+	}
+	if cursor.Sort != filters.Sort || cursor.Order != filters.Order {
+		return moviesCursor{}, ErrInvalidCursor
+	}
+
+	switch cursor.Sort {
+	case models.MoviesSortTitle:
+		var title string
+		err = json.Unmarshal(cursor.Value, &title)
+		cursor.value = title
+	case models.MoviesSortReleaseYear:
+		var year int
+		err = json.Unmarshal(cursor.Value, &year)
+		cursor.value = year
+	default:
+		var createdAt time.Time
+		err = json.Unmarshal(cursor.Value, &createdAt)
+		cursor.value = createdAt
+	}
+	if err != nil {
+		return moviesCursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"context"
+	"ozinshe_production/models"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// orderingFixture — фильм, связи которого вставлены не в том порядке, в котором их нужно вернуть
+type orderingFixture struct {
+	movieId    int
+	typeId     int
+	genres     []string
+	categories []string
+	ages       []string
+	seasons    []int
+	episodes   [][]int
+}
+
+func insertOrderingFixture(t *testing.T, conn *pgxpool.Pool) orderingFixture {
+	t.Helper()
+	ctx := context.Background()
+	suffix := uniqueSuffix()
+
+	movieId, typeId := insertTestMovie(t, conn, "Ordering")
+
+	var genreIds, categoryIds, ageIds []int
+	for _, title := range []string{"Триллер", "драма", "Боевик"} {
+		id := insertId(t, conn, `INSERT INTO genres (title) VALUES ($1) RETURNING id`, title+suffix)
+		genreIds = append(genreIds, id)
+		insertId(t, conn, `INSERT INTO movie_genres (movie_id, genre_id) VALUES ($1, $2) RETURNING genre_id`, movieId, id)
+	}
+	for _, title := range []string{"Сериалы", "Новинки", "мультфильмы"} {
+		id := insertId(t, conn, `INSERT INTO categories (title) VALUES ($1) RETURNING id`, title+suffix)
+		categoryIds = append(categoryIds, id)
+		insertId(t, conn, `INSERT INTO movie_categories (movie_id, category_id) VALUES ($1, $2) RETURNING category_id`, movieId, id)
+	}
+	for _, title := range []string{"18+", "12+", "16+"} {
+		id := insertId(t, conn, `INSERT INTO ages (title) VALUES ($1) RETURNING id`, title+suffix)
+		ageIds = append(ageIds, id)
+		insertId(t, conn, `INSERT INTO movie_ages (movie_id, age_id) VALUES ($1, $2) RETURNING age_id`, movieId, id)
+	}
+
+	// Сезоны и эпизоды вставлены вразнобой, чтобы порядок id не совпадал с порядком номеров
+	for _, season := range []struct {
+		number   int
+		episodes []int
+	}{
+		{number: 3, episodes: []int{2, 1}},
+		{number: 1, episodes: []int{3, 1, 2}},
+		{number: 2, episodes: []int{1}},
+	} {
+		seasonId := insertId(t, conn, `INSERT INTO seasons (movie_id, number) VALUES ($1, $2) RETURNING id`, movieId, season.number)
+		for _, number := range season.episodes {
+			insertId(t, conn, `INSERT INTO episodes (season_id, number) VALUES ($1, $2) RETURNING id`, seasonId, number)
+		}
+	}
+
+	t.Cleanup(func() {
+		conn.Exec(ctx, `DELETE FROM genres WHERE id = ANY($1)`, genreIds)
+		conn.Exec(ctx, `DELETE FROM categories WHERE id = ANY($1)`, categoryIds)
+		conn.Exec(ctx, `DELETE FROM ages WHERE id = ANY($1)`, ageIds)
+	})
+
+	withSuffix := func(titles ...string) []string {
+		for i := range titles {
+			titles[i] += suffix
+		}
+		return titles
+	}
+
+	return orderingFixture{
+		movieId:    movieId,
+		typeId:     typeId,
+		genres:     withSuffix("Боевик", "драма", "Триллер"),
+		categories: withSuffix("мультфильмы", "Новинки", "Сериалы"),
+		ages:       withSuffix("12+", "16+", "18+"),
+		seasons:    []int{1, 2, 3},
+		episodes:   [][]int{{1, 2, 3}, {1}, {1, 2}},
+	}
+}
+
+func assertMovieOrdering(t *testing.T, movie models.Movie, want orderingFixture) {
+	t.Helper()
+
+	var genres, categories, ages []string
+	for _, genre := range movie.Genres {
+		genres = append(genres, genre.Title)
+	}
+	for _, category := range movie.Categories {
+		categories = append(categories, category.Title)
+	}
+	for _, age := range movie.Ages {
+		ages = append(ages, age.Title)
+	}
+
+	var seasons []int
+	var episodes [][]int
+	for _, season := range movie.Seasons {
+		seasons = append(seasons, season.Number)
+		var numbers []int
+		for _, episode := range season.Episodes {
+			numbers = append(numbers, episode.Number)
+		}
+		episodes = append(episodes, numbers)
+	}
+
+	if !slices.Equal(genres, want.genres) {
+		t.Errorf("genres = %q, want %q", genres, want.genres)
+	}
+	if !slices.Equal(categories, want.categories) {
+		t.Errorf("categories = %q, want %q", categories, want.categories)
+	}
+	if !slices.Equal(ages, want.ages) {
+		t.Errorf("ages = %q, want %q", ages, want.ages)
+	}
+	if !slices.Equal(seasons, want.seasons) {
+		t.Errorf("seasons = %v, want %v", seasons, want.seasons)
+	}
+	if !slices.EqualFunc(episodes, want.episodes, slices.Equal) {
+		t.Errorf("episodes = %v, want %v", episodes, want.episodes)
+	}
+}
+
+func TestFindByIdReturnsRelationsInStableOrder(t *testing.T) {
+	conn := openTestDb(t)
+	fixture := insertOrderingFixture(t, conn)
+	repo := NewMoviesRepository(conn)
+
+	// Порядок не должен меняться от запроса к запросу
+	for i := 0; i < 5; i++ {
+		movie, err := repo.FindById(context.Background(), fixture.movieId)
+		if err != nil {
+			t.Fatalf("FindById: %v", err)
+		}
+		if movie.Id != fixture.movieId {
+			t.Fatalf("FindById returned movie %d, want %d", movie.Id, fixture.movieId)
+		}
+		if movie.MovieTypeId != fixture.typeId {
+			t.Errorf("MovieTypeId = %d, want %d", movie.MovieTypeId, fixture.typeId)
+		}
+		assertMovieOrdering(t, movie, fixture)
+	}
+}
+
+func TestFindAllReturnsRelationsInStableOrder(t *testing.T) {
+	conn := openTestDb(t)
+	fixture := insertOrderingFixture(t, conn)
+	repo := NewMoviesRepository(conn)
+
+	filters := models.Moviesfilters{TypeIds: []int{fixture.typeId}, Statuses: []string{models.MovieStatusDraft}}
+	if err := filters.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	page, err := repo.FindAll(context.Background(), filters)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if len(page.Movies) != 1 {
+		t.Fatalf("FindAll returned %d movies, want 1", len(page.Movies))
+	}
+	assertMovieOrdering(t, page.Movies[0], fixture)
+}
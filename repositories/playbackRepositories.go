@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"ozinshe_production/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrEpisodeNotFound — эпизода нет или его фильм не опубликован
+var ErrEpisodeNotFound = errors.New("episode not found")
+
+// ErrProgressRequiresEpisode — прогресс сериала сохраняется по эпизодам
+var ErrProgressRequiresEpisode = errors.New("movie has seasons, report progress per episode")
+
+type PlaybackRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPlaybackRepository(conn *pgxpool.Pool) *PlaybackRepository {
+	return &PlaybackRepository{db: conn}
+}
+
+// WatchingEntry — фильм из «Продолжить просмотр» с последней записью прогресса
+type WatchingEntry struct {
+	Movie    models.MovieCard
+	Progress models.PlaybackProgress
+}
+
+// Последняя запись прогресса по каждому фильму пользователя $1 без досмотренных до конца:
+// просмотренного фильма и сериала, у которого просмотрен последний эпизод
+const unfinishedProgressSql = `
+	SELECT * FROM (
+		SELECT DISTINCT ON (p.movie_id) p.id, p.movie_id, p.episode_id, p.position_seconds,
+		       p.duration_seconds, p.watched, p.updated_at
+		FROM playback_progress p
+		WHERE p.user_id = $1
+		ORDER BY p.movie_id, p.updated_at DESC, p.id DESC
+	) l
+	WHERE NOT l.watched OR EXISTS (
+		SELECT 1
+		FROM episodes e
+		JOIN seasons s ON s.id = e.season_id
+		JOIN seasons ns ON ns.movie_id = s.movie_id
+		JOIN episodes ne ON ne.season_id = ns.id
+		WHERE e.id = l.episode_id AND (ns.number, ne.number) > (s.number, e.number)
+	)
+`
+
+const upsertProgressSql = `
+	INSERT INTO playback_progress (user_id, movie_id, episode_id, position_seconds, duration_seconds, watched, updated_at)
+	SELECT @userId, movie_id, episode_id, @position, @duration, @watched, NOW() FROM target
+`
+
+const upsertProgressSetSql = `
+	DO UPDATE SET position_seconds = EXCLUDED.position_seconds,
+	              duration_seconds = EXCLUDED.duration_seconds,
+	              watched = EXCLUDED.watched,
+	              updated_at = EXCLUDED.updated_at
+`
+
+func progressArgs(userId int, report models.PlaybackReport) pgx.NamedArgs {
+	return pgx.NamedArgs{
+		"userId":   userId,
+		"position": report.PositionSeconds,
+		"duration": report.DurationSeconds,
+		"watched":  report.IsWatched(),
+	}
+}
+
+// SaveMovieProgress сохраняет позицию полнометражного фильма. Возвращает ErrMovieNotFound для
+// неопубликованного фильма и ErrProgressRequiresEpisode для сериала
+func (r *PlaybackRepository) SaveMovieProgress(c context.Context, userId, movieId int, report models.PlaybackReport) error {
+	args := progressArgs(userId, report)
+	args["movieId"] = movieId
+
+	var found, isSeries bool
+	err := r.db.QueryRow(c, `
+		WITH movie AS (
+			SELECT m.id, EXISTS(SELECT 1 FROM seasons s WHERE s.movie_id = m.id) AS is_series
+			FROM movies m
+			WHERE m.id = @movieId AND m.status = 'published'
+		), target AS (
+			SELECT id AS movie_id, NULL::INT AS episode_id FROM movie WHERE NOT is_series
+		), saved AS (
+			`+upsertProgressSql+`
+			ON CONFLICT (user_id, movie_id) WHERE episode_id IS NULL
+			`+upsertProgressSetSql+`
+		)
+		SELECT EXISTS(SELECT 1 FROM movie), COALESCE((SELECT is_series FROM movie), FALSE)
+	`, args).Scan(&found, &isSeries)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrMovieNotFound
+	}
+	if isSeries {
+		return ErrProgressRequiresEpisode
+	}
+	return nil
+}
+
+// SaveEpisodeProgress сохраняет позицию эпизода и возвращает id его фильма.
+// ErrEpisodeNotFound — эпизода нет или фильм не опубликован
+func (r *PlaybackRepository) SaveEpisodeProgress(c context.Context, userId, episodeId int, report models.PlaybackReport) (int, error) {
+	args := progressArgs(userId, report)
+	args["episodeId"] = episodeId
+
+	var movieId int
+	err := r.db.QueryRow(c, `
+		WITH target AS (
+			SELECT s.movie_id, e.id AS episode_id
+			FROM episodes e
+			JOIN seasons s ON s.id = e.season_id
+			JOIN movies m ON m.id = s.movie_id
+			WHERE e.id = @episodeId AND m.status = 'published'
+		), saved AS (
+			`+upsertProgressSql+`
+			ON CONFLICT (user_id, episode_id) WHERE episode_id IS NOT NULL
+			`+upsertProgressSetSql+`
+		)
+		SELECT movie_id FROM target
+	`, args).Scan(&movieId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrEpisodeNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return movieId, nil
+}
+
+// FindMovieProgress — все записи прогресса пользователя по фильму и его эпизодам
+func (r *PlaybackRepository) FindMovieProgress(c context.Context, userId, movieId int) ([]models.PlaybackProgress, error) {
+	rows, err := r.db.Query(c, `
+		SELECT movie_id, episode_id, position_seconds, duration_seconds, watched, updated_at
+		FROM playback_progress
+		WHERE user_id = $1 AND movie_id = $2
+		ORDER BY updated_at DESC, id DESC
+	`, userId, movieId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := make([]models.PlaybackProgress, 0)
+	for rows.Next() {
+		var p models.PlaybackProgress
+		if err := rows.Scan(&p.MovieId, &p.EpisodeId, &p.PositionSeconds, &p.DurationSeconds, &p.Watched, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		progress = append(progress, p)
+	}
+	return progress, rows.Err()
+}
+
+// ResetMovieProgress снимает отметки просмотра с фильма и всех его эпизодов
+func (r *PlaybackRepository) ResetMovieProgress(c context.Context, userId, movieId int) error {
+	_, err := r.db.Exec(c, `DELETE FROM playback_progress WHERE user_id = $1 AND movie_id = $2`, userId, movieId)
+	return err
+}
+
+// ContinueWatching возвращает до limit опубликованных фильмов, которые пользователь начал и не досмотрел,
+// начиная с последнего просмотренного
+func (r *PlaybackRepository) ContinueWatching(c context.Context, userId, limit int) ([]WatchingEntry, error) {
+	rows, err := r.db.Query(c, `
+		SELECT p.movie_id, p.episode_id, p.position_seconds, p.duration_seconds, p.watched, p.updated_at,
+		       `+movieCardColumns+`
+		FROM (`+unfinishedProgressSql+`) p
+		JOIN movies m ON m.id = p.movie_id
+		LEFT JOIN movie_types mt ON mt.id = m.movie_type_id
+		WHERE m.status = 'published'
+		ORDER BY p.updated_at DESC, p.id DESC
+		LIMIT $2
+	`, userId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]WatchingEntry, 0)
+	for rows.Next() {
+		var entry WatchingEntry
+		p := &entry.Progress
+		err := scanMovieCard(rows, &entry.Movie,
+			&p.MovieId, &p.EpisodeId, &p.PositionSeconds, &p.DurationSeconds, &p.Watched, &p.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
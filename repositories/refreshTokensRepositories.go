@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"ozinshe_production/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+)
+
+type RefreshTokensRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRefreshTokensRepository(conn *pgxpool.Pool) *RefreshTokensRepository {
+	return &RefreshTokensRepository{db: conn}
+}
+
+func (r *RefreshTokensRepository) Create(c context.Context, token models.RefreshToken) error {
+	_, err := r.db.Exec(c, `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`, token.UserId, token.FamilyId, token.TokenHash, token.ExpiresAt)
+	return err
+}
+
+// Rotate помечает токен использованным и выпускает вместо него новый из того же семейства.
+// Повторное предъявление уже использованного токена отзывает всё семейство
+func (r *RefreshTokensRepository) Rotate(c context.Context, oldHash string, newHash string, expiresAt time.Time) (int, error) {
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(c)
+
+	var token models.RefreshToken
+	err = tx.QueryRow(c, `
+		SELECT id, user_id, family_id, expires_at, used_at, revoked_at
+		FROM refresh_tokens WHERE token_hash = $1
+		FOR UPDATE
+	`, oldHash).Scan(&token.Id, &token.UserId, &token.FamilyId, &token.ExpiresAt, &token.UsedAt, &token.RevokedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if token.UsedAt != nil {
+		// Токен украден или отправлен повторно: отзываем все токены семейства
+		_, err = tx.Exec(c, `
+			UPDATE refresh_tokens SET revoked_at = NOW()
+			WHERE family_id = $1 AND revoked_at IS NULL
+		`, token.FamilyId)
+		if err != nil {
+			return 0, err
+		}
+		if err = tx.Commit(c); err != nil {
+			return 0, err
+		}
+		return token.UserId, ErrRefreshTokenReused
+	}
+
+	if token.RevokedAt != nil || token.ExpiresAt.Before(time.Now()) {
+		return 0, ErrRefreshTokenInvalid
+	}
+
+	_, err = tx.Exec(c, `UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1`, token.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(c, `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`, token.UserId, token.FamilyId, newHash, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		return 0, err
+	}
+
+	return token.UserId, nil
+}
+
+// Отзыв всего семейства, к которому относится токен (используется при выходе)
+func (r *RefreshTokensRepository) RevokeFamily(c context.Context, tokenHash string, userID int) error {
+	_, err := r.db.Exec(c, `
+		UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE revoked_at IS NULL AND family_id = (
+			SELECT family_id FROM refresh_tokens WHERE token_hash = $1 AND user_id = $2
+		)
+	`, tokenHash, userID)
+	return err
+}
+
+// Удаление токенов с истёкшим сроком действия
+func (r *RefreshTokensRepository) DeleteExpired(c context.Context) (int64, error) {
+	tag, err := r.db.Exec(c, `DELETE FROM refresh_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RevokedTokensRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRevokedTokensRepository(conn *pgxpool.Pool) *RevokedTokensRepository {
+	return &RevokedTokensRepository{db: conn}
+}
+
+// Отзыв токена до истечения его срока действия
+func (r *RevokedTokensRepository) Revoke(c context.Context, jti string, userID int, expiresAt time.Time) error {
+	_, err := r.db.Exec(c, `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3) ON CONFLICT (jti) DO NOTHING
+	`, jti, userID, expiresAt)
+	return err
+}
+
+func (r *RevokedTokensRepository) IsRevoked(c context.Context, jti string) (bool, error) {
+	var revoked bool
+	err := r.db.QueryRow(c, `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)`, jti).Scan(&revoked)
+	return revoked, err
+}
+
+// Удаление записей о токенах, срок действия которых уже истёк
+func (r *RevokedTokensRepository) DeleteExpired(c context.Context) (int64, error) {
+	tag, err := r.db.Exec(c, `DELETE FROM revoked_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
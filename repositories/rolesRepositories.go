@@ -4,6 +4,7 @@ import (
 	"context"
 	"ozinshe_production/models"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -15,8 +16,16 @@ func NewRolesRepository(conn *pgxpool.Pool) *RolesRepository {
 	return &RolesRepository{db: conn}
 }
 
+const selectRolesSql = `
+	SELECT r.id, r.name,
+	COALESCE(array_agg(p.name ORDER BY p.name) FILTER (WHERE p.name IS NOT NULL), '{}') AS permissions
+	FROM roles r
+	LEFT JOIN role_permissions rp ON rp.role_id = r.id
+	LEFT JOIN permissions p ON p.id = rp.permission_id
+`
+
 func (r *RolesRepository) FindAll(c context.Context) ([]models.Role, error) {
-	rows, err := r.db.Query(c, "SELECT id, name, can_edit_projects, can_edit_categories, can_edit_users, can_edit_roles, can_edit_genres, can_edit_ages FROM roles")
+	rows, err := r.db.Query(c, selectRolesSql+" GROUP BY r.id ORDER BY r.id")
 	if err != nil {
 		return nil, err
 	}
@@ -25,7 +34,7 @@ func (r *RolesRepository) FindAll(c context.Context) ([]models.Role, error) {
 	var roles []models.Role
 	for rows.Next() {
 		var role models.Role
-		err := rows.Scan(&role.Id, &role.Name, &role.CanEditProjects, &role.CanEditCategories, &role.CanEditUsers, &role.CanEditRoles, &role.CanEditGenres, &role.CanEditAges)
+		err := rows.Scan(&role.Id, &role.Name, &role.Permissions)
 		if err != nil {
 			return nil, err
 		}
@@ -37,8 +46,8 @@ func (r *RolesRepository) FindAll(c context.Context) ([]models.Role, error) {
 
 func (r *RolesRepository) FindById(c context.Context, id int) (models.Role, error) {
 	var role models.Role
-	row := r.db.QueryRow(c, "SELECT id, name, can_edit_projects, can_edit_categories, can_edit_users, can_edit_roles, can_edit_genres, can_edit_ages FROM roles WHERE id = $1", id)
-	err := row.Scan(&role.Id, &role.Name, &role.CanEditProjects, &role.CanEditCategories, &role.CanEditUsers, &role.CanEditRoles, &role.CanEditGenres, &role.CanEditAges)
+	row := r.db.QueryRow(c, selectRolesSql+" WHERE r.id = $1 GROUP BY r.id", id)
+	err := row.Scan(&role.Id, &role.Name, &role.Permissions)
 	if err != nil {
 		return models.Role{}, err
 	}
@@ -46,13 +55,24 @@ func (r *RolesRepository) FindById(c context.Context, id int) (models.Role, erro
 }
 
 func (r *RolesRepository) Create(c context.Context, role models.Role) (int, error) {
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(c)
+
 	var id int
-	row := r.db.QueryRow(c, `
-        INSERT INTO roles (name, can_edit_projects, can_edit_categories, can_edit_users, can_edit_roles, can_edit_genres, can_edit_ages) 
-        VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
-		role.Name, role.CanEditProjects, role.CanEditCategories, role.CanEditUsers, role.CanEditRoles, role.CanEditGenres, role.CanEditAges)
+	err = tx.QueryRow(c, "INSERT INTO roles (name) VALUES ($1) RETURNING id", role.Name).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = setRolePermissions(c, tx, id, role.Permissions)
+	if err != nil {
+		return 0, err
+	}
 
-	err := row.Scan(&id)
+	err = tx.Commit(c)
 	if err != nil {
 		return 0, err
 	}
@@ -60,16 +80,23 @@ func (r *RolesRepository) Create(c context.Context, role models.Role) (int, erro
 }
 
 func (r *RolesRepository) Update(c context.Context, id int, role models.Role) error {
-	_, err := r.db.Exec(c, `
-        UPDATE roles SET name=$1, can_edit_projects=$2, can_edit_categories=$3, can_edit_users=$4, can_edit_roles=$5, can_edit_genres=$6, can_edit_ages=$7
-        WHERE id=$8`,
-		role.Name, role.CanEditProjects, role.CanEditCategories, role.CanEditUsers, role.CanEditRoles, role.CanEditGenres, role.CanEditAges, id)
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(c)
 
+	_, err = tx.Exec(c, "UPDATE roles SET name=$1 WHERE id=$2", role.Name, id)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	err = setRolePermissions(c, tx, id, role.Permissions)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(c)
 }
 
 func (r *RolesRepository) Delete(c context.Context, id int) error {
@@ -78,4 +105,64 @@ func (r *RolesRepository) Delete(c context.Context, id int) error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// Все доступные права
+func (r *RolesRepository) FindAllPermissions(c context.Context) ([]models.Permission, error) {
+	rows, err := r.db.Query(c, "SELECT id, name, description FROM permissions ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var permissions []models.Permission
+	for rows.Next() {
+		var permission models.Permission
+		if err := rows.Scan(&permission.Id, &permission.Name, &permission.Description); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+// Права роли, назначенной пользователю
+func (r *RolesRepository) FindPermissionsByUserId(c context.Context, userID int) ([]string, error) {
+	rows, err := r.db.Query(c, `
+		SELECT p.name
+		FROM users u
+		JOIN role_permissions rp ON rp.role_id = u.role_id
+		JOIN permissions p ON p.id = rp.permission_id
+		WHERE u.id = $1
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := make([]string, 0)
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+// Заменяет набор прав роли
+func setRolePermissions(c context.Context, tx pgx.Tx, roleID int, permissions []string) error {
+	_, err := tx.Exec(c, "DELETE FROM role_permissions WHERE role_id = $1", roleID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(c, `
+		INSERT INTO role_permissions (role_id, permission_id)
+		SELECT $1, id FROM permissions WHERE name = ANY($2)
+	`, roleID, permissions)
+	return err
+}
@@ -2,9 +2,10 @@ package repositories
 
 import (
 	"context"
-	"strconv"
+	"fmt"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -12,6 +13,24 @@ type SearchRepository struct {
 	db *pgxpool.Pool
 }
 
+// Типы сущностей, по которым ищет админка
+const (
+	SearchTypeUser      = "User"
+	SearchTypeCategory  = "Category"
+	SearchTypeMovie     = "Movie"
+	SearchTypeGenre     = "Genre"
+	SearchTypeAge       = "Age"
+	SearchTypeMovieType = "MovieType"
+	SearchTypeSeason    = "Season"
+	SearchTypeEpisode   = "Episode"
+)
+
+// SearchTypes — все типы в порядке вывода результатов
+var SearchTypes = []string{
+	SearchTypeMovie, SearchTypeSeason, SearchTypeEpisode, SearchTypeCategory,
+	SearchTypeGenre, SearchTypeAge, SearchTypeMovieType, SearchTypeUser,
+}
+
 // SearchResult будет хранить все результаты поиска
 type SearchResult struct {
 	Type   string      `json:"type"`   // Тип объекта (User, Category, Movie)
@@ -20,6 +39,12 @@ type SearchResult struct {
 	URL    string      `json:"url"`    // URL для перехода на объект
 }
 
+// SearchAllResult — результаты поиска и общее количество совпадений по каждому типу
+type SearchAllResult struct {
+	Results []SearchResult `json:"results"`
+	Facets  map[string]int `json:"facets"`
+}
+
 type movieSearchResult struct {
 	Id    int    `json:"id"`
 	Title string `json:"title"`
@@ -32,81 +57,172 @@ type userSearchResult struct {
 	Email string `json:"email"`
 }
 
-type categorySearchResult struct {
+// Категории, возрастные ограничения и типы фильмов
+type titleSearchResult struct {
 	Id    int    `json:"id"`
 	Title string `json:"title"`
 }
 
+type genreSearchResult struct {
+	Id        int    `json:"id"`
+	Title     string `json:"title"`
+	PosterUrl string `json:"poster_url"`
+}
+
+type seasonSearchResult struct {
+	Id         int    `json:"id"`
+	Number     int    `json:"number"`
+	MovieId    int    `json:"movie_id"`
+	MovieTitle string `json:"movie_title"`
+}
+
+type episodeSearchResult struct {
+	Id           int    `json:"id"`
+	Number       int    `json:"number"`
+	SeasonId     int    `json:"season_id"`
+	SeasonNumber int    `json:"season_number"`
+	MovieId      int    `json:"movie_id"`
+	MovieTitle   string `json:"movie_title"`
+}
+
+// searchSource описывает поиск по одному типу. Запрос получает шаблон ILIKE ($1) и лимит ($2)
+// и первым столбцом возвращает общее количество совпадений
+type searchSource struct {
+	sql  string
+	scan func(rows pgx.Rows, total *int) (SearchResult, error)
+}
+
+var searchSources = map[string]searchSource{
+	SearchTypeUser: {
+		sql: `SELECT COUNT(*) OVER (), id, name, email FROM users
+			WHERE name ILIKE $1 OR email ILIKE $1 ORDER BY name, id LIMIT $2`,
+		scan: func(rows pgx.Rows, total *int) (SearchResult, error) {
+			var res userSearchResult
+			err := rows.Scan(total, &res.Id, &res.Name, &res.Email)
+			return SearchResult{Type: SearchTypeUser, ID: res.Id, Entity: res, URL: fmt.Sprintf("/admin/users/%d", res.Id)}, err
+		},
+	},
+	SearchTypeCategory: {
+		sql: `SELECT COUNT(*) OVER (), id, title FROM categories
+			WHERE title ILIKE $1 ORDER BY title, id LIMIT $2`,
+		scan: func(rows pgx.Rows, total *int) (SearchResult, error) {
+			var res titleSearchResult
+			err := rows.Scan(total, &res.Id, &res.Title)
+			return SearchResult{Type: SearchTypeCategory, ID: res.Id, Entity: res, URL: fmt.Sprintf("/admin/categories/%d", res.Id)}, err
+		},
+	},
+	SearchTypeMovie: {
+		sql: `SELECT COUNT(*) OVER (), id, title, COALESCE(cover, '') FROM movies
+			WHERE title ILIKE $1 OR description ILIKE $1 ORDER BY title, id LIMIT $2`,
+		scan: func(rows pgx.Rows, total *int) (SearchResult, error) {
+			var res movieSearchResult
+			err := rows.Scan(total, &res.Id, &res.Title, &res.Cover)
+			return SearchResult{Type: SearchTypeMovie, ID: res.Id, Entity: res, URL: fmt.Sprintf("/admin/movies/%d", res.Id)}, err
+		},
+	},
+	SearchTypeGenre: {
+		sql: `SELECT COUNT(*) OVER (), id, title, poster_url FROM genres
+			WHERE title ILIKE $1 ORDER BY title, id LIMIT $2`,
+		scan: func(rows pgx.Rows, total *int) (SearchResult, error) {
+			var res genreSearchResult
+			err := rows.Scan(total, &res.Id, &res.Title, &res.PosterUrl)
+			return SearchResult{Type: SearchTypeGenre, ID: res.Id, Entity: res, URL: fmt.Sprintf("/admin/genres/%d", res.Id)}, err
+		},
+	},
+	SearchTypeAge: {
+		sql: `SELECT COUNT(*) OVER (), id, title FROM ages
+			WHERE title ILIKE $1 ORDER BY title, id LIMIT $2`,
+		scan: func(rows pgx.Rows, total *int) (SearchResult, error) {
+			var res titleSearchResult
+			err := rows.Scan(total, &res.Id, &res.Title)
+			return SearchResult{Type: SearchTypeAge, ID: res.Id, Entity: res, URL: fmt.Sprintf("/admin/ages/%d", res.Id)}, err
+		},
+	},
+	SearchTypeMovieType: {
+		sql: `SELECT COUNT(*) OVER (), id, title FROM movie_types
+			WHERE title ILIKE $1 ORDER BY title, id LIMIT $2`,
+		scan: func(rows pgx.Rows, total *int) (SearchResult, error) {
+			var res titleSearchResult
+			err := rows.Scan(total, &res.Id, &res.Title)
+			return SearchResult{Type: SearchTypeMovieType, ID: res.Id, Entity: res, URL: fmt.Sprintf("/admin/movieTypes/%d", res.Id)}, err
+		},
+	},
+	// У сезонов и эпизодов нет названий, поэтому они находятся по названию фильма.
+	// Отдельных страниц у них нет, ссылка ведёт на фильм с деревом сезонов и эпизодов
+	SearchTypeSeason: {
+		sql: `SELECT COUNT(*) OVER (), s.id, s.number, m.id, m.title
+			FROM seasons s JOIN movies m ON m.id = s.movie_id
+			WHERE m.title ILIKE $1 ORDER BY m.title, m.id, s.number LIMIT $2`,
+		scan: func(rows pgx.Rows, total *int) (SearchResult, error) {
+			var res seasonSearchResult
+			err := rows.Scan(total, &res.Id, &res.Number, &res.MovieId, &res.MovieTitle)
+			return SearchResult{Type: SearchTypeSeason, ID: res.Id, Entity: res,
+				URL: fmt.Sprintf("/admin/movies/%d", res.MovieId)}, err
+		},
+	},
+	SearchTypeEpisode: {
+		sql: `SELECT COUNT(*) OVER (), e.id, e.number, s.id, s.number, m.id, m.title
+			FROM episodes e JOIN seasons s ON s.id = e.season_id JOIN movies m ON m.id = s.movie_id
+			WHERE m.title ILIKE $1 ORDER BY m.title, m.id, s.number, e.number LIMIT $2`,
+		scan: func(rows pgx.Rows, total *int) (SearchResult, error) {
+			var res episodeSearchResult
+			err := rows.Scan(total, &res.Id, &res.Number, &res.SeasonId, &res.SeasonNumber, &res.MovieId, &res.MovieTitle)
+			return SearchResult{Type: SearchTypeEpisode, ID: res.Id, Entity: res,
+				URL: fmt.Sprintf("/admin/movies/%d", res.MovieId)}, err
+		},
+	},
+}
+
 func NewSearchRepository(db *pgxpool.Pool) *SearchRepository {
 	return &SearchRepository{db: db}
 }
 
-func (r *SearchRepository) SearchAll(c context.Context, query string) ([]SearchResult, error) {
-	query = strings.ToLower(query)
-	var results []SearchResult
-
-	// Поиск пользователей
-	userQuery := `SELECT id, name, email FROM users WHERE name ILIKE $1 OR email ILIKE $1`
-	rows, err := r.db.Query(c, userQuery, "%"+query+"%")
-	if err != nil {
-		return nil, err
+// SearchAll ищет по каждому из types (все типы, если список пуст) не более limit результатов на тип.
+// Facets содержит общее количество совпадений по каждому запрошенному типу
+func (r *SearchRepository) SearchAll(c context.Context, query string, types []string, limit int) (SearchAllResult, error) {
+	if len(types) == 0 {
+		types = SearchTypes
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var res userSearchResult
-		if err := rows.Scan(&res.Id, &res.Name, &res.Email); err != nil {
-			return nil, err
-		}
-		results = append(results, SearchResult{
-			Type:   "User",
-			ID:     res.Id,
-			Entity: res,
-			URL:    "/users/" + strconv.Itoa(res.Id),
-		})
+	pattern := "%" + escapeLike(strings.ToLower(query)) + "%"
+	result := SearchAllResult{
+		Results: make([]SearchResult, 0),
+		Facets:  make(map[string]int, len(types)),
 	}
 
-	// Поиск категорий
-	categoryQuery := `SELECT id, title FROM categories WHERE title ILIKE $1`
-	rows, err = r.db.Query(c, categoryQuery, "%"+query+"%")
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	for _, searchType := range types {
+		source, ok := searchSources[searchType]
+		if !ok {
+			return SearchAllResult{}, fmt.Errorf("unknown search type %q", searchType)
+		}
 
-	for rows.Next() {
-		var res categorySearchResult
-		if err := rows.Scan(&res.Id, &res.Title); err != nil {
-			return nil, err
+		results, total, err := r.search(c, source, pattern, limit)
+		if err != nil {
+			return SearchAllResult{}, fmt.Errorf("search %s: %w", searchType, err)
 		}
-		results = append(results, SearchResult{
-			Type:   "Category",
-			ID:     res.Id,
-			Entity: res,
-			URL:    "/categories/" + strconv.Itoa(res.Id),
-		})
+		result.Results = append(result.Results, results...)
+		result.Facets[searchType] = total
 	}
 
-	// Поиск фильмов
-	movieQuery := `SELECT id, title, cover FROM movies WHERE title ILIKE $1 OR description ILIKE $1`
-	rows, err = r.db.Query(c, movieQuery, "%"+query+"%")
+	return result, nil
+}
+
+func (r *SearchRepository) search(c context.Context, source searchSource, pattern string, limit int) ([]SearchResult, int, error) {
+	rows, err := r.db.Query(c, source.sql, pattern, limit)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
+	var results []SearchResult
+	var total int
 	for rows.Next() {
-		var res movieSearchResult
-		if err := rows.Scan(&res.Id, &res.Title, &res.Cover); err != nil {
-			return nil, err
+		res, err := source.scan(rows, &total)
+		if err != nil {
+			return nil, 0, err
 		}
-		results = append(results, SearchResult{
-			Type:   "Movie",
-			ID:     res.Id,
-			Entity: res,
-			URL:    "/movies/" + strconv.Itoa(res.Id),
-		})
+		results = append(results, res)
 	}
 
-	return results, nil
+	return results, total, rows.Err()
 }
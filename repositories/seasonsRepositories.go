@@ -75,6 +75,25 @@ func (r *SeasonsRepository) FindAllByMovieID(c context.Context, movieID int) ([]
 	return seasons, rows.Err()
 }
 
+// Получение сезонов нескольких фильмов одним запросом: по фильмам, внутри фильма по порядку номеров
+func (r *SeasonsRepository) FindAllByMovieIDs(c context.Context, movieIDs []int) ([]models.Season, error) {
+	rows, err := r.db.Query(c, `SELECT id, movie_id, number FROM seasons WHERE movie_id = ANY($1) ORDER BY movie_id, number, id`, movieIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch seasons: %w", err)
+	}
+	defer rows.Close()
+
+	var seasons []models.Season
+	for rows.Next() {
+		var season models.Season
+		if err := rows.Scan(&season.Id, &season.MovieID, &season.Number); err != nil {
+			return nil, fmt.Errorf("failed to scan season: %w", err)
+		}
+		seasons = append(seasons, season)
+	}
+	return seasons, rows.Err()
+}
+
 // Обновление сезона
 func (r *SeasonsRepository) Update(c context.Context, season models.Season) error {
 	_, err := r.db.Exec(c, `UPDATE seasons SET number = $1 WHERE id = $2`, season.Number, season.Id)
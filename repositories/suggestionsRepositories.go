@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"ozinshe_production/models"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SuggestionsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSuggestionsRepository(conn *pgxpool.Pool) *SuggestionsRepository {
+	return &SuggestionsRepository{db: conn}
+}
+
+// Suggest возвращает до limit подсказок каждого типа, начинающихся с prefix. Шаблон LIKE собирается
+// в Go: по значению параметра вида "префикс%" планировщик может выбрать индекс idx_search_suggestions_prefix
+func (r *SuggestionsRepository) Suggest(c context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	rows, err := r.db.Query(c, `
+		SELECT kind, ref_id, value
+		FROM (
+			SELECT kind, ref_id, value,
+			       row_number() OVER (PARTITION BY kind ORDER BY weight DESC, length(value), value) AS position
+			FROM search_suggestions
+			WHERE normalized LIKE $1
+		) s
+		WHERE position <= $2
+		ORDER BY kind, position
+	`, escapeLike(strings.ToLower(prefix))+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := make([]models.Suggestion, 0)
+	for rows.Next() {
+		var suggestion models.Suggestion
+		if err := rows.Scan(&suggestion.Type, &suggestion.Id, &suggestion.Text); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, rows.Err()
+}
+
+// Refresh перестраивает индекс подсказок, не блокируя чтение
+func (r *SuggestionsRepository) Refresh(c context.Context) error {
+	_, err := r.db.Exec(c, "REFRESH MATERIALIZED VIEW CONCURRENTLY search_suggestions")
+	return err
+}
+
+// escapeLike экранирует спецсимволы LIKE, чтобы ввод пользователя совпадал буквально
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
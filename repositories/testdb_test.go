@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"ozinshe_production/migrations"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// openTestDb подключается к TEST_DB_CONNECTION_STRING и применяет миграции.
+// Без переменной тесты с базой пропускаются
+func openTestDb(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+
+	connString := os.Getenv("TEST_DB_CONNECTION_STRING")
+	if connString == "" {
+		t.Skip("TEST_DB_CONNECTION_STRING is not set")
+	}
+
+	ctx := context.Background()
+	conn, err := pgxpool.New(ctx, connString)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(conn.Close)
+
+	migrator, err := migrations.NewMigrator(conn)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	return conn
+}
+
+// insertId выполняет INSERT ... RETURNING id
+func insertId(t testing.TB, conn *pgxpool.Pool, sql string, args ...any) int {
+	t.Helper()
+
+	var id int
+	if err := conn.QueryRow(context.Background(), sql, args...).Scan(&id); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	return id
+}
+
+// uniqueSuffix делает названия тестовых записей уникальными между запусками
+func uniqueSuffix() string {
+	return fmt.Sprintf(" %d", time.Now().UnixNano())
+}
+
+// insertTestMovie создаёт фильм со своим типом и удаляет их после теста.
+// Сезоны, эпизоды, медиа и прочие записи фильма удаляются каскадом
+func insertTestMovie(tb testing.TB, conn *pgxpool.Pool, title string) (movieId, typeId int) {
+	tb.Helper()
+
+	suffix := uniqueSuffix()
+	typeId = insertId(tb, conn, `INSERT INTO movie_types (title) VALUES ($1) RETURNING id`, title+suffix)
+	movieId = insertId(tb, conn, `INSERT INTO movies (title, movie_type_id) VALUES ($1, $2) RETURNING id`, title+suffix, typeId)
+	tb.Cleanup(func() {
+		ctx := context.Background()
+		conn.Exec(ctx, `DELETE FROM movies WHERE id = $1`, movieId)
+		conn.Exec(ctx, `DELETE FROM movie_types WHERE id = $1`, typeId)
+	})
+	return movieId, typeId
+}
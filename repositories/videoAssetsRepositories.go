@@ -0,0 +1,275 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"ozinshe_production/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrVideoAssetNotFound — у эпизода или фильма нет видео с таким id
+var ErrVideoAssetNotFound = errors.New("video asset not found")
+
+// ErrMainVideoExists — у эпизода или фильма уже есть основное видео
+var ErrMainVideoExists = errors.New("episode or movie already has a main video")
+
+// ErrMovieHasSeasons — видео сериала привязывается к эпизодам, а не к фильму
+var ErrMovieHasSeasons = errors.New("movie has seasons, attach videos to its episodes")
+
+type VideoAssetsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewVideoAssetsRepository(conn *pgxpool.Pool) *VideoAssetsRepository {
+	return &VideoAssetsRepository{db: conn}
+}
+
+// Видео с дорожками одной строкой; ключи json_build_object совпадают с json-тегами моделей
+const selectVideoAssetsSql = `
+	SELECT va.id, va.episode_id, va.movie_id, va.kind, va.duration_seconds, va.hls_url, va.dash_url, va.updated_at,
+	COALESCE((
+		SELECT json_agg(json_build_object(
+			'quality', r.quality, 'width', r.width, 'height', r.height,
+			'bitrate_kbps', r.bitrate_kbps, 'codec', r.codec, 'url', r.url
+		) ORDER BY r.height DESC, r.bitrate_kbps DESC)
+		FROM video_renditions r
+		WHERE r.asset_id = va.id
+	), '[]'),
+	COALESCE((
+		SELECT json_agg(json_build_object(
+			'language', s.language, 'label', s.label, 'format', s.format, 'url', s.url, 'is_default', s.is_default
+		) ORDER BY s.id)
+		FROM video_subtitles s
+		WHERE s.asset_id = va.id
+	), '[]'),
+	COALESCE((
+		SELECT json_agg(json_build_object(
+			'language', t.language, 'label', t.label, 'codec', t.codec, 'channels', t.channels, 'is_default', t.is_default
+		) ORDER BY t.id)
+		FROM video_audio_tracks t
+		WHERE t.asset_id = va.id
+	), '[]')
+	FROM video_assets va
+`
+
+// Основное видео раньше трейлеров
+const videoAssetsOrderSql = ` ORDER BY va.kind, va.id`
+
+func scanVideoAsset(row pgx.Row) (models.VideoAsset, error) {
+	var asset models.VideoAsset
+	err := row.Scan(&asset.Id, &asset.EpisodeId, &asset.MovieId, &asset.Kind, &asset.DurationSeconds,
+		&asset.HlsURL, &asset.DashURL, &asset.UpdatedAt, &asset.Renditions, &asset.Subtitles, &asset.AudioTracks)
+	return asset, err
+}
+
+func (r *VideoAssetsRepository) findAll(c context.Context, where string, args ...any) ([]models.VideoAsset, error) {
+	rows, err := r.db.Query(c, selectVideoAssetsSql+where+videoAssetsOrderSql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assets := make([]models.VideoAsset, 0)
+	for rows.Next() {
+		asset, err := scanVideoAsset(rows)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, asset)
+	}
+	return assets, rows.Err()
+}
+
+// FindAllByOwner возвращает видео эпизода или фильма
+func (r *VideoAssetsRepository) FindAllByOwner(c context.Context, owner models.VideoOwner) ([]models.VideoAsset, error) {
+	return r.findAll(c, `
+		WHERE va.episode_id IS NOT DISTINCT FROM $1 AND va.movie_id IS NOT DISTINCT FROM $2`,
+		owner.EpisodeId, owner.MovieId)
+}
+
+// FindAllByMovie возвращает видео самого фильма и всех его эпизодов
+func (r *VideoAssetsRepository) FindAllByMovie(c context.Context, movieID int) ([]models.VideoAsset, error) {
+	return r.findAll(c, `
+		WHERE va.movie_id = $1 OR va.episode_id IN (
+			SELECT e.id FROM episodes e JOIN seasons s ON s.id = e.season_id WHERE s.movie_id = $1
+		)`, movieID)
+}
+
+func (r *VideoAssetsRepository) FindById(c context.Context, owner models.VideoOwner, id int) (models.VideoAsset, error) {
+	asset, err := scanVideoAsset(r.db.QueryRow(c, selectVideoAssetsSql+`
+		WHERE va.id = $1 AND va.episode_id IS NOT DISTINCT FROM $2 AND va.movie_id IS NOT DISTINCT FROM $3`,
+		id, owner.EpisodeId, owner.MovieId))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return asset, ErrVideoAssetNotFound
+	}
+	return asset, err
+}
+
+// Create добавляет видео с дорожками. Для фильма возвращает ErrMovieNotFound
+// или ErrMovieHasSeasons, если у фильма есть сезоны
+func (r *VideoAssetsRepository) Create(c context.Context, asset models.VideoAsset) (int, error) {
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(c)
+
+	if asset.MovieId != nil {
+		if err := lockMovie(c, tx, *asset.MovieId); err != nil {
+			return 0, err
+		}
+		var isSeries bool
+		err := tx.QueryRow(c, "SELECT EXISTS(SELECT 1 FROM seasons WHERE movie_id = $1)", *asset.MovieId).Scan(&isSeries)
+		if err != nil {
+			return 0, err
+		}
+		if isSeries {
+			return 0, ErrMovieHasSeasons
+		}
+	}
+
+	var id int
+	err = tx.QueryRow(c, `
+		INSERT INTO video_assets (episode_id, movie_id, kind, duration_seconds, hls_url, dash_url)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, asset.EpisodeId, asset.MovieId, asset.Kind, asset.DurationSeconds, asset.HlsURL, asset.DashURL).Scan(&id)
+	if err != nil {
+		return 0, videoAssetWriteError(err)
+	}
+
+	if err := setVideoTracks(c, tx, id, asset); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(c); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Update заменяет видео и все его дорожки
+func (r *VideoAssetsRepository) Update(c context.Context, asset models.VideoAsset) error {
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(c)
+
+	tag, err := tx.Exec(c, `
+		UPDATE video_assets
+		SET kind = $1, duration_seconds = $2, hls_url = $3, dash_url = $4, updated_at = NOW()
+		WHERE id = $5 AND episode_id IS NOT DISTINCT FROM $6 AND movie_id IS NOT DISTINCT FROM $7
+	`, asset.Kind, asset.DurationSeconds, asset.HlsURL, asset.DashURL, asset.Id, asset.EpisodeId, asset.MovieId)
+	if err != nil {
+		return videoAssetWriteError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrVideoAssetNotFound
+	}
+
+	if err := setVideoTracks(c, tx, asset.Id, asset); err != nil {
+		return err
+	}
+
+	return tx.Commit(c)
+}
+
+// Delete удаляет видео вместе с дорожками
+func (r *VideoAssetsRepository) Delete(c context.Context, owner models.VideoOwner, id int) error {
+	tag, err := r.db.Exec(c, `
+		DELETE FROM video_assets
+		WHERE id = $1 AND episode_id IS NOT DISTINCT FROM $2 AND movie_id IS NOT DISTINCT FROM $3
+	`, id, owner.EpisodeId, owner.MovieId)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrVideoAssetNotFound
+	}
+	return nil
+}
+
+func videoAssetWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return ErrMainVideoExists
+		case "23503":
+			return ErrEpisodeNotFound
+		}
+	}
+	return err
+}
+
+// Заменяет качества, субтитры и аудиодорожки видео. Порядок субтитров и аудио сохраняется
+func setVideoTracks(c context.Context, tx pgx.Tx, assetId int, asset models.VideoAsset) error {
+	for _, table := range []string{"video_renditions", "video_subtitles", "video_audio_tracks"} {
+		if _, err := tx.Exec(c, "DELETE FROM "+table+" WHERE asset_id = $1", assetId); err != nil {
+			return err
+		}
+	}
+
+	var qualities, codecs, urls []string
+	var widths, heights, bitrates []int
+	for _, r := range asset.Renditions {
+		qualities = append(qualities, r.Quality)
+		widths = append(widths, r.Width)
+		heights = append(heights, r.Height)
+		bitrates = append(bitrates, r.BitrateKbps)
+		codecs = append(codecs, r.Codec)
+		urls = append(urls, r.URL)
+	}
+	_, err := tx.Exec(c, `
+		INSERT INTO video_renditions (asset_id, quality, width, height, bitrate_kbps, codec, url)
+		SELECT $1, t.quality, t.width, t.height, t.bitrate_kbps, t.codec, t.url
+		FROM unnest($2::TEXT[], $3::INT[], $4::INT[], $5::INT[], $6::TEXT[], $7::TEXT[])
+		     AS t(quality, width, height, bitrate_kbps, codec, url)
+	`, assetId, qualities, widths, heights, bitrates, codecs, urls)
+	if err != nil {
+		return err
+	}
+
+	var languages, labels, formats []string
+	var defaults []bool
+	urls = nil
+	for _, s := range asset.Subtitles {
+		languages = append(languages, s.Language)
+		labels = append(labels, s.Label)
+		formats = append(formats, s.Format)
+		urls = append(urls, s.URL)
+		defaults = append(defaults, s.IsDefault)
+	}
+	_, err = tx.Exec(c, `
+		INSERT INTO video_subtitles (asset_id, language, label, format, url, is_default)
+		SELECT $1, t.language, t.label, t.format, t.url, t.is_default
+		FROM unnest($2::TEXT[], $3::TEXT[], $4::TEXT[], $5::TEXT[], $6::BOOLEAN[]) WITH ORDINALITY
+		     AS t(language, label, format, url, is_default, ord)
+		ORDER BY t.ord
+	`, assetId, languages, labels, formats, urls, defaults)
+	if err != nil {
+		return err
+	}
+
+	var channels []int
+	languages, labels, codecs, defaults = nil, nil, nil, nil
+	for _, t := range asset.AudioTracks {
+		languages = append(languages, t.Language)
+		labels = append(labels, t.Label)
+		codecs = append(codecs, t.Codec)
+		channels = append(channels, t.Channels)
+		defaults = append(defaults, t.IsDefault)
+	}
+	_, err = tx.Exec(c, `
+		INSERT INTO video_audio_tracks (asset_id, language, label, codec, channels, is_default)
+		SELECT $1, t.language, t.label, t.codec, t.channels, t.is_default
+		FROM unnest($2::TEXT[], $3::TEXT[], $4::TEXT[], $5::INT[], $6::BOOLEAN[]) WITH ORDINALITY
+		     AS t(language, label, codec, channels, is_default, ord)
+		ORDER BY t.ord
+	`, assetId, languages, labels, codecs, channels, defaults)
+	return err
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"ozinshe_production/models"
+	"testing"
+)
+
+func testVideoAsset(t *testing.T, owner models.VideoOwner, kind string) models.VideoAsset {
+	t.Helper()
+
+	asset := models.VideoAsset{
+		VideoOwner:      owner,
+		Kind:            kind,
+		DurationSeconds: 1500,
+		HlsURL:          "https://cdn.example.com/video/master.m3u8",
+		Renditions: []models.VideoRendition{
+			{Quality: "480p", Width: 854, Height: 480, BitrateKbps: 1400},
+			{Quality: "1080p", Width: 1920, Height: 1080, BitrateKbps: 5000},
+		},
+		Subtitles: []models.SubtitleTrack{
+			{Language: "ru", URL: "https://cdn.example.com/video/ru.vtt", IsDefault: true},
+			{Language: "kk", URL: "https://cdn.example.com/video/kk.vtt"},
+		},
+		AudioTracks: []models.AudioTrack{
+			{Language: "kk", IsDefault: true},
+			{Language: "ru", Label: "Дубляж"},
+			{Language: "ru", Label: "Комментарии режиссёра"},
+			{Language: "tr"},
+		},
+	}
+	if err := asset.Validate(); err != nil {
+		t.Fatalf("invalid video asset fixture: %v", err)
+	}
+	return asset
+}
+
+func TestVideoAssetsOfEpisodesAndMovies(t *testing.T) {
+	conn := openTestDb(t)
+	repo := NewVideoAssetsRepository(conn)
+	ctx := context.Background()
+
+	seriesId, _ := insertTestMovie(t, conn, "Series")
+	filmId, _ := insertTestMovie(t, conn, "Film")
+	seasonId := insertId(t, conn, `INSERT INTO seasons (movie_id, number) VALUES ($1, 1) RETURNING id`, seriesId)
+	episodeId := insertId(t, conn, `INSERT INTO episodes (season_id, number) VALUES ($1, 1) RETURNING id`, seasonId)
+
+	episode := models.EpisodeVideoOwner(episodeId)
+	mainId, err := repo.Create(ctx, testVideoAsset(t, episode, models.VideoKindMain))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := repo.Create(ctx, testVideoAsset(t, episode, models.VideoKindTrailer)); err != nil {
+		t.Fatalf("Create trailer: %v", err)
+	}
+	if _, err := repo.Create(ctx, testVideoAsset(t, episode, models.VideoKindMain)); !errors.Is(err, ErrMainVideoExists) {
+		t.Errorf("second main video = %v, want ErrMainVideoExists", err)
+	}
+	if _, err := repo.Create(ctx, testVideoAsset(t, models.MovieVideoOwner(seriesId), models.VideoKindMain)); !errors.Is(err, ErrMovieHasSeasons) {
+		t.Errorf("video of a series = %v, want ErrMovieHasSeasons", err)
+	}
+
+	asset, err := repo.FindById(ctx, episode, mainId)
+	if err != nil {
+		t.Fatalf("FindById: %v", err)
+	}
+	if len(asset.Renditions) != 2 || asset.Renditions[0].Quality != "1080p" {
+		t.Errorf("renditions = %+v, want 1080p first", asset.Renditions)
+	}
+	if len(asset.Subtitles) != 2 || asset.Subtitles[0].Language != "ru" || asset.Subtitles[0].Format != models.SubtitleFormatVTT {
+		t.Errorf("subtitles = %+v", asset.Subtitles)
+	}
+	if len(asset.AudioTracks) != 4 || asset.AudioTracks[0].Channels != 2 {
+		t.Errorf("audio tracks = %+v", asset.AudioTracks)
+	}
+
+	asset.DurationSeconds = 1620
+	asset.Subtitles = asset.Subtitles[:1]
+	if err := repo.Update(ctx, asset); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := repo.FindById(ctx, models.MovieVideoOwner(filmId), mainId); !errors.Is(err, ErrVideoAssetNotFound) {
+		t.Errorf("FindById with another owner = %v, want ErrVideoAssetNotFound", err)
+	}
+
+	filmAssetId, err := repo.Create(ctx, testVideoAsset(t, models.MovieVideoOwner(filmId), models.VideoKindMain))
+	if err != nil {
+		t.Fatalf("Create for film: %v", err)
+	}
+
+	assets, err := repo.FindAllByMovie(ctx, seriesId)
+	if err != nil {
+		t.Fatalf("FindAllByMovie: %v", err)
+	}
+	if len(assets) != 2 || assets[0].Id != mainId || assets[0].DurationSeconds != 1620 || len(assets[0].Subtitles) != 1 {
+		t.Errorf("series assets = %+v", assets)
+	}
+
+	if err := repo.Delete(ctx, episode, filmAssetId); !errors.Is(err, ErrVideoAssetNotFound) {
+		t.Errorf("Delete with another owner = %v, want ErrVideoAssetNotFound", err)
+	}
+	if err := repo.Delete(ctx, models.MovieVideoOwner(filmId), filmAssetId); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+}
@@ -16,23 +16,37 @@ func NewWatchlistRepository(conn *pgxpool.Pool) *WatchlistRepository {
 }
 
 
+// AddToWatchlist добавляет только опубликованные фильмы, иначе возвращает ErrMovieNotFound
 func (r *WatchlistRepository) AddToWatchlist(c context.Context, userID, movieID int) error {
-	_, err := r.db.Exec(c, `
-		INSERT INTO watchlist (user_id, movie_id)
-		VALUES ($1, $2) ON CONFLICT DO NOTHING;
-	`, userID, movieID)
+	var exists bool
+	err := r.db.QueryRow(c, `
+		WITH movie AS (
+			SELECT id FROM movies WHERE id = $2 AND status = 'published'
+		), inserted AS (
+			INSERT INTO watchlist (user_id, movie_id)
+			SELECT $1, id FROM movie
+			ON CONFLICT DO NOTHING
+		)
+		SELECT EXISTS(SELECT 1 FROM movie);
+	`, userID, movieID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrMovieNotFound
+	}
 
-	return err
+	return nil
 }
 
 func (r *WatchlistRepository) GetWatchlist(c context.Context, userID int) ([]models.Movie, error) {
 	rows, err := r.db.Query(c, `
 		SELECT m.id, m.title, m.release_year, m.runtime, 
 		       m.keywords, m.description, m.director, 
-		       m.producer, m.cover, m.screenshots, m.movie_type_id
+		       m.producer, m.cover, `+movieScreenshotsSql+`, m.movie_type_id
 		FROM watchlist w
 		JOIN movies m ON w.movie_id = m.id
-		WHERE w.user_id = $1
+		WHERE w.user_id = $1 AND m.status = 'published'
 		ORDER BY w.created_at DESC;
 	`, userID)
 
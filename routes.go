@@ -0,0 +1,167 @@
+package main
+
+import (
+	"ozinshe_production/handlers/admin"
+	"ozinshe_production/middlewares"
+	"ozinshe_production/models"
+)
+
+type adminHandlers struct {
+	movies          *admin.MoviesHandler
+	contents        *admin.ContentHandler
+	videoAssets     *admin.VideoAssetsHandler
+	media           *admin.MediaHandler
+	recommendations *admin.RecommendationsHandler
+	movieTypes      *admin.MovieTypesHandler
+	categories      *admin.CategoriesHandler
+	users           *admin.UsersHandler
+	roles           *admin.RolesHandler
+	genres          *admin.GenresHandler
+	ages            *admin.AgesHandler
+	search          *admin.SearchHandler
+	homepage        *admin.HomepageHandler
+}
+
+// registerAdminRoutes регистрирует маршруты админки. Каждый маршрут объявляет право, которое он требует
+func registerAdminRoutes(permitted *middlewares.PermittedGroup, h adminHandlers) {
+	// Фильмы
+	movies := permitted.Group("/admin/movies")
+	{
+		movies.GET("", models.PermMoviesRead, h.movies.FindAll)
+		movies.POST("", models.PermMoviesWrite, h.movies.Create)
+		movies.GET("/:id", models.PermMoviesRead, h.movies.FindById)
+		movies.PUT("/:id", models.PermMoviesWrite, h.movies.Update)
+		movies.DELETE("/:id", models.PermMoviesWrite, h.movies.Delete)
+		movies.PUT("/:id/status", models.PermMoviesWrite, h.movies.ChangeStatus)
+
+		// Видео полнометражного фильма без сезонов
+		assets := movies.Group("/:id/assets")
+		{
+			assets.GET("", models.PermMoviesRead, h.videoAssets.FindAll)
+			assets.POST("", models.PermMoviesWrite, h.videoAssets.Create)
+			assets.GET("/:assetId", models.PermMoviesRead, h.videoAssets.FindById)
+			assets.PUT("/:assetId", models.PermMoviesWrite, h.videoAssets.Update)
+			assets.DELETE("/:assetId", models.PermMoviesWrite, h.videoAssets.Delete)
+		}
+
+		seasons := movies.Group("/:id/seasons")
+		{
+			seasons.POST("", models.PermMoviesWrite, h.contents.AddSeasonsAndEpisodes)
+			seasons.PUT("/:seasonId/edit", models.PermMoviesWrite, h.contents.UpdateSeason)
+			seasons.DELETE("/:seasonId", models.PermMoviesWrite, h.contents.DeleteSeason)
+		}
+	}
+
+	// Сезоны и эпизоды
+	seasons := permitted.Group("/admin/seasons")
+	{
+		seasons.PUT("/:seasonId/episodes/:episodeId", models.PermMoviesWrite, h.contents.UpdateEpisode)
+		seasons.DELETE("/:seasonId/episodes/:episodeId", models.PermMoviesWrite, h.contents.DeleteEpisode)
+
+		// Видео эпизода: качества, субтитры и аудиодорожки
+		assets := seasons.Group("/:seasonId/episodes/:episodeId/assets")
+		{
+			assets.GET("", models.PermMoviesRead, h.videoAssets.FindAll)
+			assets.POST("", models.PermMoviesWrite, h.videoAssets.Create)
+			assets.GET("/:assetId", models.PermMoviesRead, h.videoAssets.FindById)
+			assets.PUT("/:assetId", models.PermMoviesWrite, h.videoAssets.Update)
+			assets.DELETE("/:assetId", models.PermMoviesWrite, h.videoAssets.Delete)
+		}
+	}
+
+	// Обложка и Скриншоты
+	media := permitted.Group("/admin/media")
+	{
+		media.GET("/movies/:id", models.PermMediaRead, h.media.GetMovieMedia)
+		media.PATCH("/movies/:id", models.PermMediaWrite, h.media.UploadMovieMedia)
+		media.POST("/movies/:id/media", models.PermMediaWrite, h.media.UploadSingleMovieMedia)
+		media.DELETE("/movies/:id/media", models.PermMediaWrite, h.media.DeleteMovieMedia)
+		media.PUT("/movies/:id/order", models.PermMediaWrite, h.media.ReorderMovieMedia)
+		media.PATCH("/movies/:id/items/:mediaId", models.PermMediaWrite, h.media.UpdateMovieMediaItem)
+		media.DELETE("/movies/:id/items/:mediaId", models.PermMediaWrite, h.media.DeleteMovieMediaItem)
+		media.POST("/gc", models.PermMediaWrite, h.media.CollectOrphanedMedia)
+	}
+
+	// Рекомендации
+	recommendations := permitted.Group("/admin/recommendations")
+	{
+		recommendations.GET("", models.PermRecommendationsRead, h.recommendations.FindAll)
+		recommendations.POST("", models.PermRecommendationsWrite, h.recommendations.Create)
+		recommendations.GET("/:id", models.PermRecommendationsRead, h.recommendations.FindById)
+		recommendations.DELETE("/:id", models.PermRecommendationsWrite, h.recommendations.Delete)
+	}
+
+	// Главная страница
+	homepage := permitted.Group("/admin/homepage")
+	{
+		homepage.GET("", models.PermHomepageRead, h.homepage.FindAll)
+		homepage.PUT("/order", models.PermHomepageWrite, h.homepage.Reorder)
+		homepage.POST("/rails", models.PermHomepageWrite, h.homepage.Create)
+		homepage.GET("/rails/:id", models.PermHomepageRead, h.homepage.FindById)
+		homepage.PUT("/rails/:id", models.PermHomepageWrite, h.homepage.Update)
+		homepage.DELETE("/rails/:id", models.PermHomepageWrite, h.homepage.Delete)
+	}
+
+	// Типы фильмов
+	movieTypes := permitted.Group("/admin/movieTypes")
+	{
+		movieTypes.GET("", models.PermMovieTypesRead, h.movieTypes.FindAll)
+		movieTypes.POST("", models.PermMovieTypesWrite, h.movieTypes.Create)
+		movieTypes.GET("/:id", models.PermMovieTypesRead, h.movieTypes.FindById)
+		movieTypes.PUT("/:id", models.PermMovieTypesWrite, h.movieTypes.Update)
+		movieTypes.DELETE("/:id", models.PermMovieTypesWrite, h.movieTypes.Delete)
+	}
+
+	// Категории
+	categories := permitted.Group("/admin/categories")
+	{
+		categories.GET("", models.PermCategoriesRead, h.categories.FindAll)
+		categories.POST("", models.PermCategoriesWrite, h.categories.Create)
+		categories.GET("/:id", models.PermCategoriesRead, h.categories.FindById)
+		categories.PUT("/:id", models.PermCategoriesWrite, h.categories.Update)
+		categories.DELETE("/:id", models.PermCategoriesWrite, h.categories.Delete)
+	}
+
+	// Пользователи
+	users := permitted.Group("/admin/users")
+	{
+		users.GET("", models.PermUsersRead, h.users.FindAll)
+		users.GET("/:id", models.PermUsersRead, h.users.FindById)
+		users.PUT("/:id/role", models.PermUsersWrite, h.users.AssignRole)
+		users.DELETE("/:id", models.PermUsersWrite, h.users.Delete)
+	}
+
+	// Роли и права
+	roles := permitted.Group("/admin/roles")
+	{
+		roles.GET("", models.PermRolesRead, h.roles.FindAll)
+		roles.POST("", models.PermRolesWrite, h.roles.Create)
+		roles.GET("/:id", models.PermRolesRead, h.roles.FindById)
+		roles.PUT("/:id", models.PermRolesWrite, h.roles.Update)
+		roles.DELETE("/:id", models.PermRolesWrite, h.roles.Delete)
+	}
+	permitted.GET("/admin/permissions", models.PermRolesRead, h.roles.FindAllPermissions)
+
+	// Жанры
+	genres := permitted.Group("/admin/genres")
+	{
+		genres.GET("", models.PermGenresRead, h.genres.FindAll)
+		genres.POST("", models.PermGenresWrite, h.genres.Create)
+		genres.GET("/:id", models.PermGenresRead, h.genres.FindById)
+		genres.PUT("/:id", models.PermGenresWrite, h.genres.Update)
+		genres.DELETE("/:id", models.PermGenresWrite, h.genres.Delete)
+	}
+
+	// Возрастные ограничения
+	ages := permitted.Group("/admin/ages")
+	{
+		ages.GET("", models.PermAgesRead, h.ages.FindAll)
+		ages.POST("", models.PermAgesWrite, h.ages.Create)
+		ages.GET("/:id", models.PermAgesRead, h.ages.FindById)
+		ages.PUT("/:id", models.PermAgesWrite, h.ages.Update)
+		ages.DELETE("/:id", models.PermAgesWrite, h.ages.Delete)
+	}
+
+	// Поиск
+	permitted.GET("/admin/search", models.PermSearchRead, h.search.SearchAll)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"ozinshe_production/middlewares"
+	"ozinshe_production/models"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminRoutePermissions перечисляет каждый маршрут админки и право, которое он должен требовать.
+// Новый маршрут в registerAdminRoutes без записи здесь роняет тест
+var adminRoutePermissions = map[string]string{
+	"GET /admin/movies":                                                   models.PermMoviesRead,
+	"POST /admin/movies":                                                  models.PermMoviesWrite,
+	"GET /admin/movies/:id":                                               models.PermMoviesRead,
+	"PUT /admin/movies/:id":                                               models.PermMoviesWrite,
+	"DELETE /admin/movies/:id":                                            models.PermMoviesWrite,
+	"PUT /admin/movies/:id/status":                                        models.PermMoviesWrite,
+	"POST /admin/movies/:id/seasons":                                      models.PermMoviesWrite,
+	"PUT /admin/movies/:id/seasons/:seasonId/edit":                        models.PermMoviesWrite,
+	"DELETE /admin/movies/:id/seasons/:seasonId":                          models.PermMoviesWrite,
+	"PUT /admin/seasons/:seasonId/episodes/:episodeId":                    models.PermMoviesWrite,
+	"DELETE /admin/seasons/:seasonId/episodes/:episodeId":                 models.PermMoviesWrite,
+	"GET /admin/movies/:id/assets":                                        models.PermMoviesRead,
+	"POST /admin/movies/:id/assets":                                       models.PermMoviesWrite,
+	"GET /admin/movies/:id/assets/:assetId":                               models.PermMoviesRead,
+	"PUT /admin/movies/:id/assets/:assetId":                               models.PermMoviesWrite,
+	"DELETE /admin/movies/:id/assets/:assetId":                            models.PermMoviesWrite,
+	"GET /admin/seasons/:seasonId/episodes/:episodeId/assets":             models.PermMoviesRead,
+	"POST /admin/seasons/:seasonId/episodes/:episodeId/assets":            models.PermMoviesWrite,
+	"GET /admin/seasons/:seasonId/episodes/:episodeId/assets/:assetId":    models.PermMoviesRead,
+	"PUT /admin/seasons/:seasonId/episodes/:episodeId/assets/:assetId":    models.PermMoviesWrite,
+	"DELETE /admin/seasons/:seasonId/episodes/:episodeId/assets/:assetId": models.PermMoviesWrite,
+
+	"GET /admin/media/movies/:id":                   models.PermMediaRead,
+	"PATCH /admin/media/movies/:id":                 models.PermMediaWrite,
+	"POST /admin/media/movies/:id/media":            models.PermMediaWrite,
+	"DELETE /admin/media/movies/:id/media":          models.PermMediaWrite,
+	"PUT /admin/media/movies/:id/order":             models.PermMediaWrite,
+	"PATCH /admin/media/movies/:id/items/:mediaId":  models.PermMediaWrite,
+	"DELETE /admin/media/movies/:id/items/:mediaId": models.PermMediaWrite,
+	"POST /admin/media/gc":                          models.PermMediaWrite,
+
+	"GET /admin/recommendations":        models.PermRecommendationsRead,
+	"POST /admin/recommendations":       models.PermRecommendationsWrite,
+	"GET /admin/recommendations/:id":    models.PermRecommendationsRead,
+	"DELETE /admin/recommendations/:id": models.PermRecommendationsWrite,
+
+	"GET /admin/homepage":              models.PermHomepageRead,
+	"PUT /admin/homepage/order":        models.PermHomepageWrite,
+	"POST /admin/homepage/rails":       models.PermHomepageWrite,
+	"GET /admin/homepage/rails/:id":    models.PermHomepageRead,
+	"PUT /admin/homepage/rails/:id":    models.PermHomepageWrite,
+	"DELETE /admin/homepage/rails/:id": models.PermHomepageWrite,
+
+	"GET /admin/movieTypes":        models.PermMovieTypesRead,
+	"POST /admin/movieTypes":       models.PermMovieTypesWrite,
+	"GET /admin/movieTypes/:id":    models.PermMovieTypesRead,
+	"PUT /admin/movieTypes/:id":    models.PermMovieTypesWrite,
+	"DELETE /admin/movieTypes/:id": models.PermMovieTypesWrite,
+
+	"GET /admin/categories":        models.PermCategoriesRead,
+	"POST /admin/categories":       models.PermCategoriesWrite,
+	"GET /admin/categories/:id":    models.PermCategoriesRead,
+	"PUT /admin/categories/:id":    models.PermCategoriesWrite,
+	"DELETE /admin/categories/:id": models.PermCategoriesWrite,
+
+	"GET /admin/users":          models.PermUsersRead,
+	"GET /admin/users/:id":      models.PermUsersRead,
+	"PUT /admin/users/:id/role": models.PermUsersWrite,
+	"DELETE /admin/users/:id":   models.PermUsersWrite,
+
+	"GET /admin/roles":        models.PermRolesRead,
+	"POST /admin/roles":       models.PermRolesWrite,
+	"GET /admin/roles/:id":    models.PermRolesRead,
+	"PUT /admin/roles/:id":    models.PermRolesWrite,
+	"DELETE /admin/roles/:id": models.PermRolesWrite,
+	"GET /admin/permissions":  models.PermRolesRead,
+
+	"GET /admin/genres":        models.PermGenresRead,
+	"POST /admin/genres":       models.PermGenresWrite,
+	"GET /admin/genres/:id":    models.PermGenresRead,
+	"PUT /admin/genres/:id":    models.PermGenresWrite,
+	"DELETE /admin/genres/:id": models.PermGenresWrite,
+
+	"GET /admin/ages":        models.PermAgesRead,
+	"POST /admin/ages":       models.PermAgesWrite,
+	"GET /admin/ages/:id":    models.PermAgesRead,
+	"PUT /admin/ages/:id":    models.PermAgesWrite,
+	"DELETE /admin/ages/:id": models.PermAgesWrite,
+
+	"GET /admin/search": models.PermSearchRead,
+}
+
+var routeParam = regexp.MustCompile(`:[^/]+`)
+
+// newAdminTestRouter собирает настоящие маршруты админки с подставными правами пользователя.
+// Обработчики созданы без репозиториев, поэтому дошедший до них запрос падает с 500 — это считается «пропущен»
+func newAdminTestRouter(permissions []string) (*gin.Engine, *middlewares.PermittedGroup) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, err any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+
+	group := r.Group("")
+	group.Use(func(c *gin.Context) {
+		c.Set("userId", 1)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	permitted := middlewares.NewPermittedGroup(group)
+	registerAdminRoutes(permitted, adminHandlers{})
+	return r, permitted
+}
+
+func TestAdminRoutesDeclareExpectedPermissions(t *testing.T) {
+	r, permitted := newAdminTestRouter(nil)
+
+	registered := make(map[string]bool)
+	for _, route := range r.Routes() {
+		key := route.Method + " " + route.Path
+		registered[key] = true
+
+		expected, covered := adminRoutePermissions[key]
+		if !covered {
+			t.Errorf("route %s is not covered by adminRoutePermissions", key)
+			continue
+		}
+
+		permission, declared := permitted.Permission(route.Method, route.Path)
+		if !declared {
+			t.Errorf("route %s has no declared permission", key)
+			continue
+		}
+		if permission != expected {
+			t.Errorf("route %s requires %q, expected %q", key, permission, expected)
+		}
+
+		// Чтение и запись разделены: GET требует только :read, остальные методы — :write
+		wantSuffix := ":write"
+		if route.Method == http.MethodGet {
+			wantSuffix = ":read"
+		}
+		if !strings.HasSuffix(permission, wantSuffix) {
+			t.Errorf("route %s requires %q, expected a %s permission", key, permission, wantSuffix)
+		}
+	}
+
+	for key := range adminRoutePermissions {
+		if !registered[key] {
+			t.Errorf("route %s is listed in adminRoutePermissions but not registered", key)
+		}
+	}
+}
+
+func TestAdminRoutesEnforcePermissions(t *testing.T) {
+	for key, permission := range adminRoutePermissions {
+		method, template, _ := strings.Cut(key, " ")
+		url := routeParam.ReplaceAllString(template, "1")
+
+		resource, access, _ := strings.Cut(permission, ":")
+		opposite := resource + ":write"
+		if access == "write" {
+			opposite = resource + ":read"
+		}
+
+		cases := []struct {
+			name        string
+			permissions []string
+			allowed     bool
+		}{
+			{"no permissions", nil, false},
+			{"unrelated permission", []string{"unrelated:write"}, false},
+			{"opposite access", []string{opposite}, false},
+			{"required permission", []string{permission}, true},
+		}
+
+		for _, tc := range cases {
+			t.Run(key+"/"+tc.name, func(t *testing.T) {
+				r, _ := newAdminTestRouter(tc.permissions)
+
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(method, url, nil)
+				r.ServeHTTP(w, req)
+
+				if tc.allowed && w.Code == http.StatusForbidden {
+					t.Errorf("%s %s: expected access with %v, got 403", method, url, tc.permissions)
+				}
+				if !tc.allowed && w.Code != http.StatusForbidden {
+					t.Errorf("%s %s: expected 403 with %v, got %d", method, url, tc.permissions, w.Code)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"ozinshe_production/models"
+	"time"
+)
+
+// MinGCGracePeriod — загрузка сначала сохраняет файл и только потом ссылку на него в базе,
+// поэтому свежие файлы без ссылок не удаляются
+const MinGCGracePeriod = 10 * time.Minute
+
+// CollectGarbage удаляет объекты, на которые не ссылается ни одно значение из referenced
+// и которые не менялись дольше grace. Ссылки могут быть ключами или выданными URL.
+// referenced нужно прочитать до вызова: объекты, появившиеся позже, защищает grace.
+// При dryRun только составляет отчёт
+func CollectGarbage(c context.Context, s Storage, referenced []string, grace time.Duration, dryRun bool) (*models.MediaGCReport, error) {
+	if grace < MinGCGracePeriod {
+		return nil, fmt.Errorf("grace period must be at least %s", MinGCGracePeriod)
+	}
+
+	keys := make(map[string]struct{}, len(referenced))
+	for _, ref := range referenced {
+		if ref != "" {
+			keys[KeyFromURL(ref)] = struct{}{}
+		}
+	}
+
+	report := &models.MediaGCReport{
+		DryRun:      dryRun,
+		GracePeriod: grace.String(),
+		StartedAt:   time.Now(),
+		Orphans:     []models.OrphanedMedia{},
+	}
+	cutoff := report.StartedAt.Add(-grace)
+
+	// Удаляем после обхода, чтобы не менять хранилище во время листинга
+	err := s.List(c, func(object Object) error {
+		report.Scanned++
+		switch _, ok := keys[object.Key]; {
+		case ok:
+			report.Referenced++
+		case object.ModTime.After(cutoff):
+			report.Recent++
+		default:
+			report.Orphans = append(report.Orphans, models.OrphanedMedia{
+				Key:        object.Key,
+				Size:       object.Size,
+				ModifiedAt: object.ModTime,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	for i := range report.Orphans {
+		orphan := &report.Orphans[i]
+		if err := s.Delete(c, orphan.Key); err != nil {
+			if c.Err() != nil {
+				return nil, c.Err()
+			}
+			orphan.Error = err.Error()
+			report.Failed++
+			continue
+		}
+		report.Deleted++
+		report.FreedBytes += orphan.Size
+	}
+	return report, nil
+}
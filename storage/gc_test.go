@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCollectGarbage(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocal(dir, "/media")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	old := time.Now().Add(-48 * time.Hour)
+	for _, key := range []string{"cover.jpg", "legacy.jpg", "orphan.jpg", "fresh.jpg"} {
+		if err := s.Put(ctx, key, bytes.NewReader([]byte(key)), int64(len(key)), "image/jpeg"); err != nil {
+			t.Fatal(err)
+		}
+		if key != "fresh.jpg" {
+			os.Chtimes(filepath.Join(dir, key), old, old)
+		}
+	}
+	// Старые записи могут хранить ссылку вместо ключа
+	referenced := []string{"cover.jpg", "/images/legacy.jpg", "", "missing.jpg"}
+
+	report, err := CollectGarbage(ctx, s, referenced, 24*time.Hour, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if report.Scanned != 4 || report.Referenced != 2 || report.Recent != 1 || report.Deleted != 0 {
+		t.Errorf("dry run report = %+v", report)
+	}
+	if len(report.Orphans) != 1 || report.Orphans[0].Key != "orphan.jpg" {
+		t.Fatalf("orphans = %+v, want orphan.jpg", report.Orphans)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "orphan.jpg")); err != nil {
+		t.Errorf("dry run deleted the file: %v", err)
+	}
+
+	report, err = CollectGarbage(ctx, s, referenced, 24*time.Hour, false)
+	if err != nil {
+		t.Fatalf("CollectGarbage: %v", err)
+	}
+	if report.Deleted != 1 || report.FreedBytes != int64(len("orphan.jpg")) || report.Failed != 0 {
+		t.Errorf("report = %+v", report)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "orphan.jpg")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("orphan left: %v", err)
+	}
+	for _, key := range []string{"cover.jpg", "legacy.jpg", "fresh.jpg"} {
+		if _, err := os.Stat(filepath.Join(dir, key)); err != nil {
+			t.Errorf("%s deleted: %v", key, err)
+		}
+	}
+
+	if _, err := CollectGarbage(ctx, s, nil, time.Second, true); err == nil {
+		t.Error("grace period below the minimum accepted")
+	}
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalPutURLDelete(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocal(dir, "/images/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	content := []byte("poster bytes")
+	if err := s.Put(ctx, "cover.jpg", bytes.NewReader(content), int64(len(content)), "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	saved, err := os.ReadFile(filepath.Join(dir, "cover.jpg"))
+	if err != nil || !bytes.Equal(saved, content) {
+		t.Fatalf("saved file = %q, %v", saved, err)
+	}
+
+	url, err := s.URL(ctx, "cover.jpg")
+	if err != nil || url != "/images/cover.jpg" {
+		t.Errorf("URL = %q, %v", url, err)
+	}
+
+	object, err := s.Get(ctx, "cover.jpg")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, _ := io.ReadAll(object)
+	object.Close()
+	if !bytes.Equal(got, content) {
+		t.Errorf("Get = %q, want %q", got, content)
+	}
+
+	if err := s.Delete(ctx, "cover.jpg"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cover.jpg")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file still exists: %v", err)
+	}
+	if err := s.Delete(ctx, "cover.jpg"); err != nil {
+		t.Errorf("Delete of a missing file: %v", err)
+	}
+	if _, err := s.Get(ctx, "cover.jpg"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a missing file = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalRejectsKeysOutsideDir(t *testing.T) {
+	s, err := NewLocal(t.TempDir(), "/images")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"", "../secret", "/etc/passwd", "a/../../b", `..\secret`} {
+		err := s.Put(context.Background(), key, bytes.NewReader(nil), 0, "")
+		if !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q) = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
+
+func TestKeyFromURL(t *testing.T) {
+	cases := map[string]string{
+		"cover.jpg":                   "cover.jpg",
+		"/images/cover.jpg":           "cover.jpg",
+		"https://cdn/media/cover.jpg": "cover.jpg",
+		"http://localhost:9000/media/cover.jpg?X-Amz-Signature=abc": "cover.jpg",
+	}
+	for url, want := range cases {
+		if got := KeyFromURL(url); got != want {
+			t.Errorf("KeyFromURL(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"ozinshe_production/models"
+)
+
+// ResolveURL возвращает ссылку на объект; пустой ключ — пустая ссылка
+func ResolveURL(c context.Context, s Storage, key string) (string, error) {
+	if key == "" {
+		return "", nil
+	}
+	return s.URL(c, key)
+}
+
+// ResolveMovieMedia заменяет ключи обложки и скриншотов фильма ссылками и заполняет URL кадров галереи
+func ResolveMovieMedia(c context.Context, s Storage, media *models.MovieMedia) error {
+	if media.Cover != nil {
+		if *media.Cover == "" {
+			media.Cover = nil
+		} else {
+			url, err := s.URL(c, *media.Cover)
+			if err != nil {
+				return err
+			}
+			media.Cover = &url
+		}
+	}
+
+	urls, err := URLs(c, s, media.Screenshots)
+	if err != nil {
+		return err
+	}
+	media.Screenshots = urls
+
+	for i := range media.Items {
+		if media.Items[i].URL, err = ResolveURL(c, s, media.Items[i].Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolveCovers заменяет ключи обложек карточек ссылками
+func ResolveCovers(c context.Context, s Storage, cards []models.MovieCard) error {
+	for i := range cards {
+		if cards[i].Cover == nil {
+			continue
+		}
+		url, err := ResolveURL(c, s, *cards[i].Cover)
+		if err != nil {
+			return err
+		}
+		if url == "" {
+			cards[i].Cover = nil
+		} else {
+			cards[i].Cover = &url
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrInvalidKey — ключ пустой или выходит за пределы хранилища
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// ErrNotFound — объекта с таким ключом нет
+var ErrNotFound = errors.New("storage object not found")
+
+// Storage хранит загруженные медиафайлы. В базе сохраняется только ключ объекта,
+// а клиент получает ссылку из URL
+type Storage interface {
+	// Put сохраняет объект под ключом key, перезаписывая существующий
+	Put(c context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get открывает объект на чтение; ErrNotFound, если его нет
+	Get(c context.Context, key string) (io.ReadCloser, error)
+	// Delete удаляет объект. Отсутствующий объект ошибкой не считается
+	Delete(c context.Context, key string) error
+	// URL возвращает публичную или подписанную ссылку на объект
+	URL(c context.Context, key string) (string, error)
+	// List вызывает fn для каждого объекта хранилища. Ошибка fn прерывает обход и возвращается
+	List(c context.Context, fn func(Object) error) error
+}
+
+// Object — объект хранилища, найденный List
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// ValidateKey проверяет, что ключ непустой и не выходит за пределы хранилища
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+// URLs возвращает ссылки на объекты в том же порядке. Пустой ключ даёт пустую ссылку
+func URLs(c context.Context, s Storage, keys []string) ([]string, error) {
+	urls := make([]string, 0, len(keys))
+	for _, key := range keys {
+		url, err := ResolveURL(c, s, key)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+	return urls, nil
+}
+
+// KeyFromURL достаёт ключ из ссылки, выданной URL. Ключи медиафайлов плоские, поэтому
+// это последний сегмент пути без параметров подписи. Голый ключ возвращается как есть
+func KeyFromURL(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	if i := strings.LastIndex(url, "/"); i >= 0 {
+		url = url[i+1:]
+	}
+	return url
+}